@@ -0,0 +1,11 @@
+data "ysafe_folder" "shared" {
+    path = "/shared/certificates"           # (Required) Full path of the folder
+}
+
+output "certificates_owner" {
+    value = data.ysafe_folder.shared.owner
+}
+
+output "certificates_max_file_versions" {
+    value = data.ysafe_folder.shared.max_file_versions
+}
@@ -0,0 +1,14 @@
+data "ysafe_roles" "all" {
+    expand = true                           # (Optional) Also read the permissions of every role
+}
+
+output "roles_allowing_deletes" {
+    value = [
+        for r in data.ysafe_roles.all.roles : r.name if contains(r.permissions, "DeleteSecret")
+    ]
+}
+
+resource "ysafe_team" "readers" {
+    name  = "readers"
+    roles = [for name in data.ysafe_roles.all.names : name if startswith(name, "read-")]
+}
@@ -0,0 +1,14 @@
+data "ysafe_secret" "db" {
+    path = "/prod/db/admin"                 # Path of the secret
+    version = 3                             # (Optional) Version to read, the latest when omitted
+}
+
+resource "kubernetes_secret" "db" {
+    metadata {
+        name = "db-admin"
+    }
+    data = {
+        username = data.ysafe_secret.db.password[0].username
+        password = data.ysafe_secret.db.password[0].password
+    }
+}
@@ -0,0 +1,16 @@
+data "ysafe_secrets" "expiring" {
+    prefix = "/prod/"                       # (Optional) Only secrets whose path starts with this, default "/"
+    regex = "/tls/"                         # (Optional) Only secrets whose path matches this regular expression
+    expiring_within_days = 30               # (Optional) Only secrets expiring in less than this many days
+}
+
+output "expiring_certificates" {
+    value = {
+        for s in data.ysafe_secrets.expiring.secrets : s.path => s.expiry
+    }
+}
+
+data "ysafe_secret" "prod" {
+    for_each = toset(data.ysafe_secrets.expiring.paths)
+    path     = each.value
+}
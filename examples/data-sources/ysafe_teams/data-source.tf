@@ -0,0 +1,14 @@
+data "ysafe_teams" "all" {
+    expand = true                           # (Optional) Also read the roles of every team
+}
+
+output "team_roles" {
+    value = {
+        for t in data.ysafe_teams.all.teams : t.name => t.roles
+    }
+}
+
+resource "ysafe_user_assignment" "alice" {
+    email = "alice@example.com"
+    teams = [for name in data.ysafe_teams.all.names : name if startswith(name, "platform-")]
+}
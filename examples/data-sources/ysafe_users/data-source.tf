@@ -0,0 +1,17 @@
+data "ysafe_users" "platform" {
+    team = "platform"                       # (Optional) Only members of this team
+    role = "secret-writers"                 # (Optional) Only users given this role directly
+}
+
+output "platform_users" {
+    value = {
+        for u in data.ysafe_users.platform.users : u.email => u.roles
+    }
+}
+
+resource "ysafe_user_assignment" "platform" {
+    for_each = toset(data.ysafe_users.platform.emails)
+    email    = each.value
+    roles    = ["auditors"]
+    additive = true
+}
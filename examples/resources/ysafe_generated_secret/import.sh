@@ -0,0 +1 @@
+terraform import ysafe_generated_secret.session_key /prod/app/session_key
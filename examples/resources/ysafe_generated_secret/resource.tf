@@ -0,0 +1,17 @@
+resource "ysafe_generated_secret" "session_key" {
+    path = "/prod/app/session_key"          # Path of the secret
+    length = 32                             # Number of bytes (or characters for "alphanumeric") to generate
+    format = "random"                       # (Optional) "random" for arbitrary bytes, "alphanumeric" for letters and digits
+    rotate_interval = 2592000               # (Optional) Seconds after which a new value is generated on the next apply
+
+    keepers = {                             # (Optional) Changing any of these values generates a new value
+        rotated = "2026-10"
+    }
+}
+
+resource "ysafe_generated_secret" "api_token" {
+    path = "/prod/app/api_token"
+    length = 40
+    format = "alphanumeric"
+    deletion_mode = "destroy"
+}
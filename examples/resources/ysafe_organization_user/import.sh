@@ -0,0 +1 @@
+terraform import ysafe_organization_user.alice alice@example.com
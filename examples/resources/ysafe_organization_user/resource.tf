@@ -0,0 +1,8 @@
+resource "ysafe_organization_user" "alice" {
+    email = "alice@example.com"             # Invited on create, removed on destroy
+}
+
+resource "ysafe_organization_user" "owner" {
+    email = "owner@example.com"
+    prevent_destroy_if_owner = true         # (Optional) Refuse to remove the owner of the organization, default true
+}
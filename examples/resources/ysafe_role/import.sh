@@ -0,0 +1 @@
+terraform import ysafe_role.secret_readers secret-readers
@@ -0,0 +1,8 @@
+resource "ysafe_role" "secret_readers" {
+    name = "secret-readers"                 # Unique name of the role
+    permissions = [                         # Operations the role allows, without the "Op" prefix
+        "GetSecret",
+        "ListSecrets",
+        "QuickListSecrets",
+    ]
+}
@@ -0,0 +1 @@
+terraform import ysafe_secret.db /prod/db/admin
\ No newline at end of file
@@ -0,0 +1,21 @@
+resource "ysafe_secret" "db" {
+    path = "/prod/db/admin"                 # Path of the secret
+    expiry = 1767225600                     # (Optional) Unix time after which the secret is reported as expired
+    rotate_interval = 2592000               # (Optional) Seconds after which plans warn the secret is overdue for rotation
+    deletion_mode = "soft"                  # (Optional) "soft" to keep the secret recoverable, "destroy" to remove it for good
+    restore_deleted = true                  # (Optional) Restore a soft deleted secret at the same path instead of failing
+
+    password {                              # Exactly one of password, card, note, identity, key_value, key, certificate, private_key
+        username = "admin"
+        password = var.db_password
+        url      = "postgres://db.example.com:5432"
+    }
+}
+
+resource "ysafe_secret" "tls" {
+    path = "/prod/tls/key"
+
+    private_key {
+        pem = file("tls.key")
+    }
+}
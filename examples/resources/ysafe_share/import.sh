@@ -0,0 +1 @@
+terraform import ysafe_share.docs /docs
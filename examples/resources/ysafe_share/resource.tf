@@ -0,0 +1,19 @@
+resource "ysafe_access_policy" "docs" {
+    name = "docs"
+}
+
+resource "ysafe_share" "docs" {
+    object_path = "/${ysafe_access_policy.docs.id}"   # Full path of the folder
+
+    rule {
+        subject = ysafe_team.platform.id    # Email of a user or name of a team
+        subject_type = "team"               # (Optional) "user" or "team", default "user"
+        operations = ["GetSecret", "ListSecrets"]
+    }
+
+    rule {
+        action = "deny"                     # (Optional) "allow" or "deny", default "allow"
+        subject = "contractor@example.com"
+        operations = ["DeleteSecret"]
+    }
+}
@@ -0,0 +1 @@
+terraform import ysafe_team.platform platform
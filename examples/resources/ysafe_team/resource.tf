@@ -0,0 +1,12 @@
+resource "ysafe_role" "secret_readers" {
+    name = "secret-readers"
+    permissions = ["GetSecret", "ListSecrets"]
+}
+
+resource "ysafe_team" "platform" {
+    name = "platform"                       # Unique name of the team
+    roles = [                               # (Optional) Roles of the team
+        ysafe_role.secret_readers.id,       # Reference roles by id so that they are created first
+        "auditors",                         # Roles managed elsewhere must already exist
+    ]
+}
@@ -0,0 +1 @@
+terraform import ysafe_user_assignment.alice alice@example.com
@@ -0,0 +1,11 @@
+resource "ysafe_user_assignment" "alice" {
+    email = "alice@example.com"             # Member of the organization
+    roles = [ysafe_role.secret_readers.id]  # (Optional) Roles of the user
+    teams = [ysafe_team.platform.id]        # (Optional) Teams of the user
+}
+
+resource "ysafe_user_assignment" "bob" {
+    email = "bob@example.com"
+    teams = ["platform"]
+    additive = true                         # (Optional) Keep roles and teams given outside of Terraform
+}
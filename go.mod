@@ -9,6 +9,7 @@ require github.com/hashicorp/terraform-plugin-sdk/v2 v2.37.0
 require (
 	github.com/fxamacker/cbor/v2 v2.8.0
 	github.com/gorilla/websocket v1.5.3
+	github.com/hashicorp/go-cty v1.5.0
 	google.golang.org/protobuf v1.36.6
 )
 
@@ -23,7 +24,6 @@ require (
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-checkpoint v0.5.0 // indirect
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
-	github.com/hashicorp/go-cty v1.5.0 // indirect
 	github.com/hashicorp/go-hclog v1.6.3 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/hashicorp/go-plugin v1.6.3 // indirect
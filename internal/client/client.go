@@ -2,7 +2,10 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"crypto/tls"
@@ -19,25 +22,75 @@ import (
 )
 
 var (
-	globalClient *Client
-	clientMu     sync.Mutex
+	clients   = map[string]*pendingClient{}
+	clientsMu sync.Mutex
 )
 
-func GetClient(token, endpoint, pin string) *Client {
-	clientMu.Lock()
-	defer clientMu.Unlock()
-	if globalClient == nil {
-		globalClient, _ = New(context.Background(), endpoint, token, pin)
+// pendingClient is an entry of clients. done is closed once c and err are
+// set, so callers asking for a client that is still signing in wait for it
+// instead of signing in again.
+type pendingClient struct {
+	done chan struct{}
+	c    *Client
+	err  error
+}
+
+// GetClient returns the authenticated client for cfg. Provider instances
+// configured with the same endpoint and credentials share one session, every
+// other configuration gets its own. The registry is only locked to look up
+// and add entries, so a slow sign in holds up the callers sharing its
+// configuration but no other.
+func GetClient(ctx context.Context, cfg Config) (*Client, error) {
+	key := cfg.fingerprint()
+	clientsMu.Lock()
+	p, ok := clients[key]
+	if !ok {
+		p = &pendingClient{done: make(chan struct{})}
+		clients[key] = p
+	}
+	clientsMu.Unlock()
+
+	if !ok {
+		p.c, p.err = New(ctx, cfg)
+		if p.err != nil {
+			// Let the next caller try again.
+			clientsMu.Lock()
+			delete(clients, key)
+			clientsMu.Unlock()
+		}
+		close(p.done)
+		return p.c, p.err
+	}
+	select {
+	case <-p.done:
+		return p.c, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	return globalClient
 }
 
 type Client struct {
+	cfg    Config
+	url    string
+	dialer websocket.Dialer
+	Email  string
+
+	// Organization is the name of the organization the signed in user is a
+	// member of. It is empty for individual users.
+	Organization string
+
+	// slots bounds the number of sessions in use at once, idle holds the
+	// signed in sessions that are free to be borrowed by the next request.
+	slots chan struct{}
 	mu    sync.Mutex
-	conn  *websocket.Conn
-	token string
-	pin   string
-	Email string
+	idle  []*session
+}
+
+// session is one signed in connection. It carries a single request at a
+// time, the pool in Client is what lets requests run concurrently.
+type session struct {
+	conn     *websocket.Conn
+	lastUsed time.Time
 }
 
 var caCertBytes = []byte(`-----BEGIN CERTIFICATE-----
@@ -78,32 +131,124 @@ MKW0h3F9JW/NX/F7oYtWrxljheH8n2BrkDybAjEAlCxkLE0vQTYcFzrR24oogyw6
 VkgTm92+jiqJTO5SSA9QUa092S5cTKiHkH2cOM6m
 -----END CERTIFICATE-----`)
 
-func New(ctx context.Context, endpoint, token string, pin string) (*Client, error) {
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, err
+const (
+	// DefaultEndpoint is the ysafe service used when no endpoint is configured.
+	DefaultEndpoint = "wss://files.ysafe.io:5577"
+
+	DefaultPoolSize = 4
+)
+
+// Config holds everything needed to open an authenticated session.
+type Config struct {
+	Endpoint string
+	Token    string
+	Pin      string
+
+	// CACertPEM is appended to the embedded SSL.com roots so that
+	// self-hosted instances signed by a private CA can be trusted.
+	CACertPEM          []byte
+	TLSServerName      string
+	InsecureSkipVerify bool
+
+	// MaxRetries bounds how often a read-only request is retried after the
+	// connection failed. RetryMaxBackoff caps the wait between two attempts.
+	MaxRetries      int
+	RetryMaxBackoff time.Duration
+
+	// PoolSize is the maximum number of connections, and therefore of
+	// requests in flight, per client.
+	PoolSize int
+}
+
+func (cfg Config) endpoint() string {
+	if cfg.Endpoint == "" {
+		return DefaultEndpoint
 	}
+	return cfg.Endpoint
+}
+
+// fingerprint identifies the session cfg would open without keeping the
+// credentials themselves around as map keys.
+func (cfg Config) fingerprint() string {
+	h := sha256.New()
+	for _, field := range []string{
+		cfg.endpoint(),
+		cfg.Token,
+		cfg.Pin,
+		string(cfg.CACertPEM),
+		cfg.TLSServerName,
+		fmt.Sprint(cfg.InsecureSkipVerify),
+		fmt.Sprint(cfg.MaxRetries),
+		cfg.RetryMaxBackoff.String(),
+		fmt.Sprint(cfg.PoolSize),
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (cfg Config) tlsConfig() (*tls.Config, error) {
 	caCertPool := x509.NewCertPool()
 	if ok := caCertPool.AppendCertsFromPEM(caCertBytes); !ok {
 		return nil, fmt.Errorf("failed to load certificates")
 	}
-	tlsConfig := &tls.Config{
-		RootCAs: caCertPool,
-	}
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-		TLSClientConfig:  tlsConfig,
+	if len(cfg.CACertPEM) > 0 {
+		if ok := caCertPool.AppendCertsFromPEM(cfg.CACertPEM); !ok {
+			return nil, fmt.Errorf("failed to load custom CA certificates: no PEM certificates found")
+		}
 	}
+	return &tls.Config{
+		RootCAs:            caCertPool,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}, nil
+}
 
-	conn, _, err := dialer.DialContext(ctx, u.String(), nil)
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	u, err := url.Parse(cfg.endpoint())
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	poolSize := cfg.PoolSize
+	if poolSize <= 0 {
+		poolSize = DefaultPoolSize
+	}
+	client := &Client{
+		cfg: cfg,
+		url: u.String(),
+		dialer: websocket.Dialer{
+			HandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:  tlsConfig,
+		},
+		slots: make(chan struct{}, poolSize),
+	}
+	conn, signIn, err := client.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	client.idle = append(client.idle, &session{conn: conn, lastUsed: time.Now()})
+	client.Email = signIn.Email
+	client.Organization = signIn.GetOrgnization()
+	return client, nil
+}
 
-	data, err := base64.StdEncoding.DecodeString(token)
+// connect dials the endpoint and signs in with the configured token and pin.
+// It returns the reply to the sign in along with the connection.
+func (c *Client) connect(ctx context.Context) (*websocket.Conn, *response.SignIn, error) {
+	data, err := base64.StdEncoding.DecodeString(c.cfg.Token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hex decode token: %w", err)
+		return nil, nil, fmt.Errorf("%w: failed to decode token: %v", ErrSignInFailed, err)
 	}
+	conn, _, err := c.dialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	pin := c.cfg.Pin
 	signin := request.SignIn{
 		Data: data,
 		Pin:  &pin,
@@ -113,61 +258,167 @@ func New(ctx context.Context, endpoint, token string, pin string) (*Client, erro
 			SignIn: &signin,
 		},
 	}
-	encReq, err := proto.Marshal(&req)
+	responseObj, err := roundTrip(ctx, conn, &req)
 	if err != nil {
-		return nil, err
+		conn.Close()
+		return nil, nil, err
 	}
-	if err := conn.WriteMessage(websocket.BinaryMessage, encReq); err != nil {
-		return nil, err
+	signInResp := responseObj.GetSignIn()
+	if signInResp == nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("unexpected response to sign in")
+	}
+	if signInResp.Status != response.Status_SUCCESS {
+		conn.Close()
+		return nil, nil, fmt.Errorf("%w with status %s", ErrSignInFailed, signInResp.Status)
 	}
+	return conn, signInResp, nil
+}
 
-	var responseObj response.Response
-	_, resp, err := conn.ReadMessage()
+// roundTrip writes req and reads the response within the deadline of ctx.
+// Cancelling ctx interrupts a blocked read or write; the connection must not
+// be used again after roundTrip returned an error.
+func roundTrip(ctx context.Context, conn *websocket.Conn, req *request.Request) (*response.Response, error) {
+	protoReq, err := proto.Marshal(req)
 	if err != nil {
 		return nil, err
 	}
-	if err := proto.Unmarshal(resp, &responseObj); err != nil {
-		return nil, err
+	deadline, _ := ctx.Deadline()
+	conn.SetWriteDeadline(deadline)
+	conn.SetReadDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		// websocket.Conn keeps its write deadline in an unguarded field, so
+		// interrupt through the network connection, which is safe to use
+		// from another goroutine.
+		conn.NetConn().SetDeadline(time.Now())
+	})
+	defer stop()
 
+	if err := conn.WriteMessage(websocket.BinaryMessage, protoReq); err != nil {
+		return nil, contextError(ctx, err)
+	}
+	_, resp, err := conn.ReadMessage()
+	if err != nil {
+		return nil, contextError(ctx, err)
 	}
-	if responseObj.GetSignIn().Status != response.Status_SUCCESS {
+	if !stop() && ctx.Err() != nil {
+		// The deadlines were moved while the response was already in, the
+		// connection would time out on its next use.
+		return nil, ctx.Err()
+	}
+	var responseObj response.Response
+	if err := proto.Unmarshal(resp, &responseObj); err != nil {
 		return nil, err
 	}
-	Email := responseObj.GetSignIn().Email
-	client := &Client{
-		mu:    sync.Mutex{},
-		conn:  conn,
-		Email: Email,
-		pin:   pin,
-		token: token,
+	return &responseObj, nil
+}
+
+// contextError prefers the reason ctx ended over the i/o timeout it caused.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
 	}
-	return client, nil
+	return err
 }
 
+// Send is SendContext without a deadline.
 func (c *Client) Send(req *request.Request) (*response.Response, error) {
-	clientMu.Lock()
-	defer clientMu.Unlock()
+	return c.SendContext(context.Background(), req)
+}
+
+// SendContext sends req and waits for its response until ctx is done.
+// Concurrent calls are spread over up to PoolSize connections. When a
+// connection turns out to be closed the client signs in again on a new one;
+// requests that only read state are retried with backoff, up to the
+// configured number of times.
+func (c *Client) SendContext(ctx context.Context, req *request.Request) (*response.Response, error) {
 	if c == nil {
 		return nil, nil
 	}
-	protoReq, _ := proto.Marshal(req)
-	if c.conn == nil {
-		return nil, fmt.Errorf("connection is nil")
-	}
-	err := c.conn.WriteMessage(websocket.BinaryMessage, protoReq)
+	sess, err := c.acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
-	_, resp, err := c.conn.ReadMessage()
-	if err != nil {
-		return nil, err
+	defer c.release(sess)
+	idempotent := isIdempotent(req)
+	for attempt := 0; ; attempt++ {
+		resp, sent, err := c.send(ctx, sess, req)
+		if err == nil {
+			if proto.Equal(resp, &response.Response{}) {
+				return nil, fmt.Errorf("empty response")
+			}
+			return resp, nil
+		}
+		if ctx.Err() != nil || errors.Is(err, ErrSignInFailed) || (sent && !idempotent) || attempt >= c.cfg.MaxRetries {
+			return nil, err
+		}
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
-	var responseObj response.Response
-	if err := proto.Unmarshal(resp, &responseObj); err != nil {
-		return nil, err
+}
+
+// acquire waits for a free slot and hands out an idle session, or an empty
+// one that send connects on first use.
+func (c *Client) acquire(ctx context.Context) (*session, error) {
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	if proto.Equal(&responseObj, &response.Response{}) {
-		return nil, fmt.Errorf("empty response")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := len(c.idle); n > 0 {
+		sess := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		return sess, nil
 	}
-	return &responseObj, nil
+	return &session{}, nil
+}
+
+// release returns sess to the pool, unless its connection was lost.
+func (c *Client) release(sess *session) {
+	if sess.conn != nil {
+		c.mu.Lock()
+		c.idle = append(c.idle, sess)
+		c.mu.Unlock()
+	}
+	<-c.slots
+}
+
+// send performs a single attempt at req on sess, reconnecting first if the
+// previous connection was lost. sent reports whether req may have reached
+// the server.
+func (c *Client) send(ctx context.Context, sess *session, req *request.Request) (resp *response.Response, sent bool, err error) {
+	if sess.conn != nil && !isIdempotent(req) && time.Since(sess.lastUsed) > idleCheckAfter {
+		// Writes are never retried, so make sure an idle connection has not
+		// been closed by the server or a load balancer before using it.
+		if _, err := roundTrip(ctx, sess.conn, pingRequest()); err != nil {
+			sess.drop()
+		}
+	}
+	if sess.conn == nil {
+		conn, _, err := c.connect(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		sess.conn = conn
+	}
+	resp, err = roundTrip(ctx, sess.conn, req)
+	if err != nil {
+		sess.drop()
+		return nil, true, err
+	}
+	sess.lastUsed = time.Now()
+	return resp, true, nil
+}
+
+// drop closes the connection so that the next request reconnects.
+func (s *session) drop() {
+	s.conn.Close()
+	s.conn = nil
 }
@@ -0,0 +1,257 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func testConfig(srv *ysafetest.Server, token, pin string) client.Config {
+	return client.Config{
+		Endpoint:  srv.URL,
+		Token:     token,
+		Pin:       pin,
+		CACertPEM: []byte(srv.CACertPEM()),
+	}
+}
+
+func echo(t *testing.T, c *client.Client) {
+	t.Helper()
+	req := &request.Request{
+		Operation: &request.Request_Echo{
+			Echo: &request.Echo{Data: []byte("ping")},
+		},
+	}
+	resp, err := c.Send(req)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if string(resp.GetEcho().GetData()) != "ping" {
+		t.Fatalf("unexpected echo response: %v", resp)
+	}
+}
+
+func TestGetClientPerCredentials(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	tokenA := srv.AddAccount("a@example.com", "111111")
+	tokenB := srv.AddAccount("b@example.com", "222222")
+	ctx := context.Background()
+
+	a, err := client.GetClient(ctx, testConfig(srv, tokenA, "111111"))
+	if err != nil {
+		t.Fatalf("GetClient(a): %v", err)
+	}
+	b, err := client.GetClient(ctx, testConfig(srv, tokenB, "222222"))
+	if err != nil {
+		t.Fatalf("GetClient(b): %v", err)
+	}
+	if a == b {
+		t.Fatal("different credentials share a client")
+	}
+	if a.Email != "a@example.com" || b.Email != "b@example.com" {
+		t.Fatalf("unexpected emails %q and %q", a.Email, b.Email)
+	}
+
+	again, err := client.GetClient(ctx, testConfig(srv, tokenA, "111111"))
+	if err != nil {
+		t.Fatalf("GetClient(a) again: %v", err)
+	}
+	if again != a {
+		t.Fatal("identical configuration did not reuse the client")
+	}
+
+	echo(t, b)
+	echo(t, a)
+	reqs := srv.Requests()
+	if len(reqs) != 2 || reqs[0].Email != "b@example.com" || reqs[1].Email != "a@example.com" {
+		t.Fatalf("requests landed on the wrong sessions: %+v", reqs)
+	}
+}
+
+func TestGetClientSignInFailure(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+
+	if _, err := client.GetClient(context.Background(), testConfig(srv, token, "999999")); err == nil {
+		t.Fatal("expected sign in with the wrong pin to fail")
+	}
+}
+
+func TestGetClientSharesSignIn(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	cfg := testConfig(srv, token, "111111")
+
+	const callers = 8
+	got := make(chan *client.Client, callers)
+	for range callers {
+		go func() {
+			c, err := client.GetClient(context.Background(), cfg)
+			if err != nil {
+				t.Errorf("GetClient: %v", err)
+			}
+			got <- c
+		}()
+	}
+	first := <-got
+	for range callers - 1 {
+		if c := <-got; c != first {
+			t.Fatal("concurrent callers with one configuration got different clients")
+		}
+	}
+	if n := srv.SignIns(); n != 1 {
+		t.Fatalf("signed in %d times, want 1", n)
+	}
+}
+
+func TestGetClientSlowEndpointBlocksNoOther(t *testing.T) {
+	// Accepts connections and never answers, so the TLS handshake hangs.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	accepted := make(chan struct{}, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+			accepted <- struct{}{}
+		}
+	}()
+
+	slowCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := client.GetClient(slowCtx, client.Config{Endpoint: "wss://" + ln.Addr().String(), Token: "dG9rZW4=", Pin: "111111"})
+		slowDone <- err
+	}()
+	<-accepted
+
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	start := time.Now()
+	if _, err := client.GetClient(context.Background(), testConfig(srv, token, "111111")); err != nil {
+		t.Fatalf("GetClient: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("GetClient waited %v for the unrelated slow endpoint", elapsed)
+	}
+	select {
+	case err := <-slowDone:
+		t.Fatalf("slow endpoint returned early: %v", err)
+	default:
+	}
+	cancel()
+	if err := <-slowDone; err == nil {
+		t.Fatal("expected the slow endpoint to fail")
+	}
+}
+
+func TestSendReconnects(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	cfg := testConfig(srv, token, "111111")
+	cfg.MaxRetries = 2
+	cfg.RetryMaxBackoff = 10 * time.Millisecond
+	c, err := client.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	echo(t, c)
+	srv.DropConnections()
+	echo(t, c)
+
+	if got := srv.SignIns(); got != 2 {
+		t.Fatalf("expected the client to sign in again, got %d sign ins", got)
+	}
+	for _, r := range srv.Requests() {
+		if r.Email != "a@example.com" {
+			t.Fatalf("request served for %s after reconnecting", r.Email)
+		}
+	}
+}
+
+func TestSendDoesNotRetryWrites(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	cfg := testConfig(srv, token, "111111")
+	cfg.MaxRetries = 2
+	cfg.RetryMaxBackoff = 10 * time.Millisecond
+	c, err := client.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	srv.DropConnections()
+	_, err = c.Send(&request.Request{
+		Operation: &request.Request_CreateFolder{
+			CreateFolder: &request.CreateFolder{Name: "docs", ParentPath: "/"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected the write on a dropped connection to fail")
+	}
+	echo(t, c)
+	if got := len(srv.Requests()); got != 1 {
+		t.Fatalf("expected only the echo to reach the server, got %d requests", got)
+	}
+}
+
+func TestSendContextDeadline(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	cfg := testConfig(srv, token, "111111")
+	cfg.MaxRetries = 3
+	c, err := client.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_Echo{Echo: &request.Echo{}},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("SendContext returned %s after the deadline", elapsed)
+	}
+
+	srv.SetLatency(0)
+	echo(t, c)
+}
+
+func TestSendContextCancel(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	c, err := client.New(context.Background(), testConfig(srv, token, "111111"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_Echo{Echo: &request.Echo{}},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the request to be cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/response"
+)
+
+var (
+	ErrNotFound        = errors.New("object not found")
+	ErrExists          = errors.New("object already exists")
+	ErrAccessDenied    = errors.New("access denied")
+	ErrLocked          = errors.New("object locked")
+	ErrQuotaExceeded   = errors.New("quota exceeded")
+	ErrInvalidRequest  = errors.New("invalid request")
+	ErrBackend         = errors.New("backend error")
+	ErrUnexpectedReply = errors.New("unexpected response")
+)
+
+// statusErrors maps every failure status to the error callers can match
+// with errors.Is.
+var statusErrors = map[response.Status]error{
+	response.Status_OBJECT_NOT_FOUND:       ErrNotFound,
+	response.Status_OBJECT_EXISTS:          ErrExists,
+	response.Status_ACCESS_DENIED:          ErrAccessDenied,
+	response.Status_AUTHENTICATION_FAILURE: ErrAccessDenied,
+	response.Status_OBJECT_LOCKED:          ErrLocked,
+	response.Status_QUOTA_EXCEEDED:         ErrQuotaExceeded,
+	response.Status_STORAGE_FULL:           ErrQuotaExceeded,
+	response.Status_INVALID_REQUEST:        ErrInvalidRequest,
+}
+
+// retryableStatuses are the failures caused by a temporary condition on the
+// server rather than by the request.
+var retryableStatuses = map[response.Status]bool{
+	response.Status_BACKEND_ERROR:       true,
+	response.Status_STORAGE_FAILURE:     true,
+	response.Status_STORAGE_UNAVAILABLE: true,
+	response.Status_OBJECT_LOCKED:       true,
+}
+
+// StatusError is returned when the server answers an operation with any
+// status but SUCCESS.
+type StatusError struct {
+	// Op is the name of the request operation, e.g. "CreateFolder".
+	Op     string
+	Status response.Status
+	// Message is the explanation sent by the server, if any.
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s failed with status %s: %s", e.Op, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s failed with status %s", e.Op, e.Status)
+}
+
+// Unwrap lets errors.Is match the error against ErrNotFound and friends.
+func (e *StatusError) Unwrap() error {
+	if err, ok := statusErrors[e.Status]; ok {
+		return err
+	}
+	return ErrBackend
+}
+
+// Retryable reports whether sending the same request again later may
+// succeed.
+func (e *StatusError) Retryable() bool {
+	return retryableStatuses[e.Status]
+}
+
+// IsRetryable reports whether err is a StatusError for a temporary failure.
+func IsRetryable(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Retryable()
+}
+
+// statusResponse is implemented by every operation specific response.
+type statusResponse[R any] interface {
+	*R
+	GetStatus() response.Status
+}
+
+// check validates the operation specific part of a response: it has to be
+// the one that was asked for, and it has to report success.
+func check[T statusResponse[R], R any](op string, r T) error {
+	if r == nil {
+		return fmt.Errorf("%s: %w", op, ErrUnexpectedReply)
+	}
+	status := r.GetStatus()
+	if status == response.Status_SUCCESS {
+		return nil
+	}
+	err := &StatusError{Op: op, Status: status}
+	if m, ok := any(r).(interface{ GetMessage() string }); ok {
+		err.Message = m.GetMessage()
+	}
+	return err
+}
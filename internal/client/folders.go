@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+)
+
+// ErrNotAFolder is returned by GetFolder when the path names a file.
+var ErrNotAFolder = errors.New("not a folder")
+
+// GetMetaFromPath returns the metadata of the file or folder at path.
+func (c *Client) GetMetaFromPath(ctx context.Context, path string) (*response.MetaObj, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetMetaFromPath{
+			GetMetaFromPath: &request.GetMetaFromPath{
+				Path: path,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetGetMetaFromPath()
+	if err := check("GetMetaFromPath", r); err != nil {
+		return nil, err
+	}
+	return r.Meta, nil
+}
+
+// GetFolder returns the metadata of the folder at path.
+func (c *Client) GetFolder(ctx context.Context, path string) (*response.Folder, error) {
+	meta, err := c.GetMetaFromPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	folder := meta.GetFolderMeta()
+	if folder == nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrNotAFolder)
+	}
+	return folder, nil
+}
+
+// CreateFolder creates the folder name inside parentPath. policy may be nil.
+func (c *Client) CreateFolder(ctx context.Context, parentPath, name string, policy *request.Policy) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_CreateFolder{
+			CreateFolder: &request.CreateFolder{
+				Name:       name,
+				ParentPath: parentPath,
+				TypeOfPath: request.TypeOfPath_TFolder,
+				Policy:     policy,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("CreateFolder", resp.GetCreateFolder())
+}
+
+// RemoveFolder moves the folder at path to the trash, or deletes it for
+// good when permanent is set.
+func (c *Client) RemoveFolder(ctx context.Context, path string, permanent bool) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_RemoveFolder{
+			RemoveFolder: &request.RemoveFolder{
+				FolderFullPath: path,
+				IsPerm:         permanent,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("RemoveFolder", resp.GetRemoveFolder())
+}
@@ -0,0 +1,147 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func newTestClient(t *testing.T, srv *ysafetest.Server) *client.Client {
+	t.Helper()
+	token := srv.AddAccount("a@example.com", "111111")
+	c, err := client.New(context.Background(), testConfig(srv, token, "111111"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func metaResponse(status response.Status, meta *response.MetaObj) *response.Response {
+	return &response.Response{Operation: &response.Response_GetMetaFromPath{
+		GetMetaFromPath: &response.GetMetaFromPath{Status: status, Meta: meta},
+	}}
+}
+
+func TestGetFolder(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		resp    *response.Response
+		wantErr error
+	}{
+		{
+			name: "folder",
+			resp: metaResponse(response.Status_SUCCESS, &response.MetaObj{
+				Meta: &response.MetaObj_FolderMeta{FolderMeta: &response.Folder{Name: "docs"}},
+			}),
+		},
+		{
+			name: "file",
+			resp: metaResponse(response.Status_SUCCESS, &response.MetaObj{
+				Meta: &response.MetaObj_FileMeta{FileMeta: &response.File{Name: "docs"}},
+			}),
+			wantErr: client.ErrNotAFolder,
+		},
+		{
+			name:    "missing",
+			resp:    metaResponse(response.Status_OBJECT_NOT_FOUND, nil),
+			wantErr: client.ErrNotFound,
+		},
+		{
+			name:    "denied",
+			resp:    metaResponse(response.Status_ACCESS_DENIED, nil),
+			wantErr: client.ErrAccessDenied,
+		},
+		{
+			name:    "storage",
+			resp:    metaResponse(response.Status_STORAGE_READ_ONLY, nil),
+			wantErr: client.ErrBackend,
+		},
+		{
+			name: "wrong operation",
+			resp: &response.Response{Operation: &response.Response_CreateFolder{
+				CreateFolder: &response.CreateFolder{},
+			}},
+			wantErr: client.ErrUnexpectedReply,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv.Handle(func(string, *request.Request) *response.Response { return tt.resp })
+			folder, err := c.GetFolder(ctx, "/docs")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetFolder: %v", err)
+			}
+			if folder.Name != "docs" {
+				t.Fatalf("unexpected folder %v", folder)
+			}
+		})
+	}
+}
+
+func TestCreateFolder(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	var got *request.CreateFolder
+	srv.Handle(func(_ string, req *request.Request) *response.Response {
+		got = req.GetCreateFolder()
+		return &response.Response{Operation: &response.Response_CreateFolder{
+			CreateFolder: &response.CreateFolder{Status: response.Status_OBJECT_EXISTS},
+		}}
+	})
+
+	err := c.CreateFolder(context.Background(), "/", "docs", nil)
+	if !errors.Is(err, client.ErrExists) {
+		t.Fatalf("expected ErrExists, got %v", err)
+	}
+	if got.GetName() != "docs" || got.GetParentPath() != "/" || got.GetTypeOfPath() != request.TypeOfPath_TFolder {
+		t.Fatalf("unexpected request %v", got)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_RemoveFolder{
+			RemoveFolder: &response.RemoveFolder{Status: response.Status_OBJECT_LOCKED},
+		}}
+	})
+	err := c.RemoveFolder(ctx, "/docs", false)
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected a StatusError, got %v", err)
+	}
+	if statusErr.Op != "RemoveFolder" || statusErr.Status != response.Status_OBJECT_LOCKED {
+		t.Fatalf("unexpected error %+v", statusErr)
+	}
+	if !errors.Is(err, client.ErrLocked) || !client.IsRetryable(err) {
+		t.Fatalf("expected a retryable ErrLocked, got %v", err)
+	}
+
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_RemoveFolder{
+			RemoveFolder: &response.RemoveFolder{Status: response.Status_STORAGE_READ_ONLY},
+		}}
+	})
+	err = c.RemoveFolder(ctx, "/docs", false)
+	if !errors.Is(err, client.ErrBackend) || client.IsRetryable(err) {
+		t.Fatalf("expected a permanent ErrBackend, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+)
+
+// AddPin creates a pin for the signed in user and returns the token data
+// that authenticates together with it.
+func (c *Client) AddPin(ctx context.Context, pin *request.AddPin) (*response.AddPin, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_AddPin{
+			AddPin: pin,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetAddPin()
+	if err := check("AddPin", r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdatePinOps replaces the operations and objects a pin is allowed to use.
+func (c *Client) UpdatePinOps(ctx context.Context, update *request.UpdatePinOps) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_UpdatePinOps{
+			UpdatePinOps: update,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("UpdatePinOps", resp.GetUpdatePinOps())
+}
+
+// DeletePin revokes a pin.
+func (c *Client) DeletePin(ctx context.Context, pin *request.DeletePin) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_DeletePin{
+			DeletePin: pin,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("DeletePin", resp.GetDeletePin())
+}
+
+// ListPins returns every pin of the signed in user, following the page
+// tokens until the server reports the last page.
+func (c *Client) ListPins(ctx context.Context) ([]*response.PinInList, error) {
+	var pins []*response.PinInList
+	var pageToken []byte
+	for {
+		resp, err := c.SendContext(ctx, &request.Request{
+			Operation: &request.Request_ListPins{
+				ListPins: &request.ListPins{
+					PageToken: pageToken,
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		r := resp.GetListPins()
+		if err := check("ListPins", r); err != nil {
+			return nil, err
+		}
+		pins = append(pins, r.PinObjects...)
+		if r.IsLast || len(r.PageToken) == 0 {
+			return pins, nil
+		}
+		pageToken = r.PageToken
+	}
+}
@@ -0,0 +1,58 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func TestListPinsPages(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	pages := map[string]*response.ListPins{
+		"": {
+			PinObjects: []*response.PinInList{{Name: "one"}, {Name: "two"}},
+			PageToken:  []byte("second"),
+		},
+		"second": {
+			PinObjects: []*response.PinInList{{Name: "three"}},
+			IsLast:     true,
+		},
+	}
+	srv.Handle(func(_ string, req *request.Request) *response.Response {
+		page := pages[string(req.GetListPins().GetPageToken())]
+		return &response.Response{Operation: &response.Response_ListPins{ListPins: page}}
+	})
+
+	pins, err := c.ListPins(context.Background())
+	if err != nil {
+		t.Fatalf("ListPins: %v", err)
+	}
+	var names []string
+	for _, pin := range pins {
+		names = append(names, pin.Name)
+	}
+	if len(names) != 3 || names[0] != "one" || names[2] != "three" {
+		t.Fatalf("unexpected pins %v", names)
+	}
+}
+
+func TestAddPinFailure(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	message := "pin name taken"
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_AddPin{AddPin: &response.AddPin{
+			Status:  response.Status_OBJECT_EXISTS,
+			Message: &message,
+		}}}
+	})
+
+	_, err := c.AddPin(context.Background(), &request.AddPin{Pin: "123456"})
+	if err == nil || err.Error() != "AddPin failed with status OBJECT_EXISTS: pin name taken" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
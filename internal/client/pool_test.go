@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func concurrentEchos(t *testing.T, c *client.Client, n int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := fmt.Sprintf("ping-%d", i)
+			resp, err := c.Send(&request.Request{
+				Operation: &request.Request_Echo{Echo: &request.Echo{Data: []byte(data)}},
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got := string(resp.GetEcho().GetData()); got != data {
+				errs <- fmt.Errorf("request %q got the response to %q", data, got)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPoolRunsRequestsConcurrently(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	srv.SetLatency(20 * time.Millisecond)
+	cfg := testConfig(srv, token, "111111")
+	cfg.PoolSize = 3
+	c, err := client.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	concurrentEchos(t, c, 24)
+
+	if got := srv.MaxInFlight(); got != 3 {
+		t.Errorf("expected 3 requests in flight at most and at least once, got %d", got)
+	}
+	if got := srv.SignIns(); got != 3 {
+		t.Errorf("expected one sign in per pooled connection, got %d", got)
+	}
+	if got := len(srv.Requests()); got != 24 {
+		t.Errorf("expected 24 requests, got %d", got)
+	}
+}
+
+func TestPoolRecoversDroppedConnections(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount("a@example.com", "111111")
+	cfg := testConfig(srv, token, "111111")
+	cfg.PoolSize = 4
+	cfg.MaxRetries = 3
+	cfg.RetryMaxBackoff = 10 * time.Millisecond
+	c, err := client.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	concurrentEchos(t, c, 16)
+	srv.DropConnections()
+	concurrentEchos(t, c, 16)
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// AddRole creates a role. It fails with ErrExists when the name is taken.
+func (c *Client) AddRole(ctx context.Context, role *request.AddRole) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_AddRole{
+			AddRole: role,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("AddRole", resp.GetAddRole())
+}
+
+// UpdateRole replaces the permissions of a role.
+func (c *Client) UpdateRole(ctx context.Context, role *request.UpdateRole) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_UpdateRole{
+			UpdateRole: role,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("UpdateRole", resp.GetUpdateRole())
+}
+
+// RemoveRole deletes the role called name.
+func (c *Client) RemoveRole(ctx context.Context, name string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_RemoveRole{
+			RemoveRole: &request.RemoveRole{
+				Name: name,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("RemoveRole", resp.GetRemoveRole())
+}
+
+// GetRole returns the role called name.
+func (c *Client) GetRole(ctx context.Context, name string) (*request.Role, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetRole{
+			GetRole: &request.GetRole{
+				Name: name,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetGetRole()
+	if err := check("GetRole", r); err != nil {
+		return nil, err
+	}
+	var role request.Role
+	if err := proto.Unmarshal(r.Role, &role); err != nil {
+		return nil, fmt.Errorf("GetRole: decoding role: %w", err)
+	}
+	return &role, nil
+}
+
+// ListRoles returns the names of all roles of the organization.
+func (c *Client) ListRoles(ctx context.Context) ([]string, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_ListRoles{
+			ListRoles: &request.ListRoles{},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetListRoles()
+	if err := check("ListRoles", r); err != nil {
+		return nil, err
+	}
+	return r.Roles, nil
+}
+
+// AddTeam creates a team holding the named roles. It fails with ErrNotFound
+// when one of the roles does not exist.
+func (c *Client) AddTeam(ctx context.Context, team *request.AddTeam) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_AddTeam{
+			AddTeam: team,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("AddTeam", resp.GetAddTeam())
+}
+
+// UpdateTeam replaces the roles of a team.
+func (c *Client) UpdateTeam(ctx context.Context, team *request.UpdateTeam) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_UpdateTeam{
+			UpdateTeam: team,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("UpdateTeam", resp.GetUpdateTeam())
+}
+
+// RemoveTeam deletes the team called name.
+func (c *Client) RemoveTeam(ctx context.Context, name string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_RemoveTeam{
+			RemoveTeam: &request.RemoveTeam{
+				Name: name,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("RemoveTeam", resp.GetRemoveTeam())
+}
+
+// GetTeam returns the team called name. The server identifies its roles by
+// uuid only, in RoleUuids.
+func (c *Client) GetTeam(ctx context.Context, name string) (*request.Team, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetTeam{
+			GetTeam: &request.GetTeam{
+				Name: name,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetGetTeam()
+	if err := check("GetTeam", r); err != nil {
+		return nil, err
+	}
+	var team request.Team
+	if err := proto.Unmarshal(r.Team, &team); err != nil {
+		return nil, fmt.Errorf("GetTeam: decoding team: %w", err)
+	}
+	return &team, nil
+}
+
+// ListTeams returns the names of all teams of the organization.
+func (c *Client) ListTeams(ctx context.Context) ([]string, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_ListTeams{
+			ListTeams: &request.ListTeams{},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetListTeams()
+	if err := check("ListTeams", r); err != nil {
+		return nil, err
+	}
+	return r.Teams, nil
+}
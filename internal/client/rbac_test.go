@@ -0,0 +1,100 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func TestRoleLifecycle(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	readers := []request.Op{request.Op_OpGetSecret, request.Op_OpListSecrets}
+	if err := c.AddRole(ctx, &request.AddRole{Name: "readers", Permissions: readers}); err != nil {
+		t.Fatalf("AddRole: %v", err)
+	}
+	if err := c.AddRole(ctx, &request.AddRole{Name: "readers"}); !errors.Is(err, client.ErrExists) {
+		t.Fatalf("AddRole on a taken name = %v, want ErrExists", err)
+	}
+	if err := c.UpdateRole(ctx, &request.UpdateRole{Name: "readers", Permissions: []request.Op{request.Op_OpAddUser}}); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	role, err := c.GetRole(ctx, "readers")
+	if err != nil {
+		t.Fatalf("GetRole: %v", err)
+	}
+	// OpAddUser is the zero value of the enum and must survive the round trip.
+	if len(role.Permissions) != 1 || role.Permissions[0] != request.Op_OpAddUser || len(role.Uuid) == 0 {
+		t.Fatalf("GetRole = %v, want readers allowed OpAddUser", role)
+	}
+	names, err := c.ListRoles(ctx)
+	if err != nil || len(names) != 1 || names[0] != "readers" {
+		t.Fatalf("ListRoles = %v, %v, want [readers]", names, err)
+	}
+	if err := c.RemoveRole(ctx, "readers"); err != nil {
+		t.Fatalf("RemoveRole: %v", err)
+	}
+	if _, err := c.GetRole(ctx, "readers"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetRole after remove = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTeamLifecycle(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	for _, name := range []string{"readers", "writers"} {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+	err := c.AddTeam(ctx, &request.AddTeam{Name: "ops", Roles: []string{"readers", "missing"}})
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("AddTeam with a missing role = %v, want ErrNotFound", err)
+	}
+	if err := c.AddTeam(ctx, &request.AddTeam{Name: "ops", Roles: []string{"readers"}}); err != nil {
+		t.Fatalf("AddTeam: %v", err)
+	}
+	if err := c.UpdateTeam(ctx, &request.UpdateTeam{Name: "ops", Roles: []string{"readers", "writers"}}); err != nil {
+		t.Fatalf("UpdateTeam: %v", err)
+	}
+	team, err := c.GetTeam(ctx, "ops")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	writers, _ := srv.Role("writers")
+	if len(team.RoleUuids) != 2 || string(team.RoleUuids[1]) != string(writers.Uuid) {
+		t.Fatalf("GetTeam = %v, want the uuids of readers and writers", team)
+	}
+	names, err := c.ListTeams(ctx)
+	if err != nil || len(names) != 1 || names[0] != "ops" {
+		t.Fatalf("ListTeams = %v, %v, want [ops]", names, err)
+	}
+	if err := c.RemoveTeam(ctx, "ops"); err != nil {
+		t.Fatalf("RemoveTeam: %v", err)
+	}
+	if _, err := c.GetTeam(ctx, "ops"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetTeam after remove = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetRoleCorrupt(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_GetRole{GetRole: &response.GetRole{
+			Role: []byte{0xff, 0xff},
+		}}}
+	})
+	if _, err := c.GetRole(context.Background(), "readers"); err == nil {
+		t.Fatal("GetRole succeeded on corrupt data")
+	}
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+)
+
+const (
+	DefaultMaxRetries      = 3
+	DefaultRetryMaxBackoff = 30 * time.Second
+
+	retryBaseBackoff = 250 * time.Millisecond
+
+	// idleCheckAfter is how long a connection may sit unused before it is
+	// probed ahead of a request that cannot be retried.
+	idleCheckAfter = 30 * time.Second
+)
+
+// ErrSignInFailed is returned when the server rejects the token and pin.
+// Retrying does not help, so such failures are returned immediately.
+var ErrSignInFailed = errors.New("sign in failed")
+
+// isIdempotent reports whether req only reads state, so that sending it a
+// second time after a lost response is harmless.
+func isIdempotent(req *request.Request) bool {
+	switch req.Operation.(type) {
+	case *request.Request_Echo,
+		*request.Request_GetMetaFromPath,
+		*request.Request_GetMetaFromSharedPath,
+		*request.Request_GetFolder,
+		*request.Request_GetFileHead,
+		*request.Request_List,
+		*request.Request_ListFiles,
+		*request.Request_ListPins,
+		*request.Request_GetSecret,
+		*request.Request_ListSecrets,
+		*request.Request_QuickListSecrets,
+		*request.Request_ListSecretSubkeys,
+		*request.Request_GetRole,
+		*request.Request_ListRoles,
+		*request.Request_GetTeam,
+		*request.Request_ListTeams,
+		*request.Request_GetUser,
+		*request.Request_ListUsers,
+		*request.Request_GetRolesAndTeamsOfUser,
+		*request.Request_ListShares,
+		*request.Request_IsOperationAllowed:
+		return true
+	}
+	return false
+}
+
+func pingRequest() *request.Request {
+	return &request.Request{
+		Operation: &request.Request_Echo{
+			Echo: &request.Echo{},
+		},
+	}
+}
+
+// backoff returns how long to wait before the retry following attempt,
+// doubling from retryBaseBackoff up to the configured maximum with jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	limit := c.cfg.RetryMaxBackoff
+	if limit <= 0 {
+		limit = DefaultRetryMaxBackoff
+	}
+	d := retryBaseBackoff << attempt
+	if d <= 0 || d > limit {
+		d = limit
+	}
+	return d/2 + rand.N(d/2+1)
+}
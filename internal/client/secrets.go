@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// AddSecret stores a new secret. It fails with ErrExists when a secret,
+// deleted or not, already uses the path.
+func (c *Client) AddSecret(ctx context.Context, secret *request.AddSecret) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_AddSecret{
+			AddSecret: secret,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("AddSecret", resp.GetAddSecret())
+}
+
+// UpdateSecret changes the expiry of a secret and, when SecretData is set,
+// stores the data as a new version.
+func (c *Client) UpdateSecret(ctx context.Context, update *request.UpdateSecret) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_UpdateSecret{
+			UpdateSecret: update,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("UpdateSecret", resp.GetUpdateSecret())
+}
+
+// GetSecret returns the secret at path together with all of its versions.
+func (c *Client) GetSecret(ctx context.Context, path string) (*response.Secret, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetSecret{
+			GetSecret: &request.GetSecret{
+				Path: path,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetGetSecret()
+	if err := check("GetSecret", r); err != nil {
+		return nil, err
+	}
+	var secret response.Secret
+	if err := proto.Unmarshal(r.Data, &secret); err != nil {
+		return nil, fmt.Errorf("GetSecret: decoding secret: %w", err)
+	}
+	return &secret, nil
+}
+
+// DeleteSecret soft deletes the secret at path. It can be brought back with
+// UndeleteSecret until it is destroyed.
+func (c *Client) DeleteSecret(ctx context.Context, path string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_DeleteSecret{
+			DeleteSecret: &request.DeleteSecret{
+				Path: path,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("DeleteSecret", resp.GetDeleteSecret())
+}
+
+// DestroySecret removes the secret at path and all of its versions for good.
+func (c *Client) DestroySecret(ctx context.Context, path string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_DestroySecret{
+			DestroySecret: &request.DestroySecret{
+				Path: path,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("DestroySecret", resp.GetDestroySecret())
+}
+
+// UndeleteSecret restores a soft deleted secret with all of its versions.
+func (c *Client) UndeleteSecret(ctx context.Context, path string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_UndeleteSecret{
+			UndeleteSecret: &request.UndeleteSecret{
+				Path: path,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("UndeleteSecret", resp.GetUndeleteSecret())
+}
+
+// GetRandomBytes asks the server for size random bytes, drawn from
+// [A-Za-z0-9] when alphanumeric is set.
+func (c *Client) GetRandomBytes(ctx context.Context, size uint32, alphanumeric bool) ([]byte, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetRandomBytes{
+			GetRandomBytes: &request.GetRandomBytes{
+				IsAlphanumeric: alphanumeric,
+				Size:           size,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetGetRandomBytes()
+	if err := check("GetRandomBytes", r); err != nil {
+		return nil, err
+	}
+	if len(r.RandomBytes) != int(size) {
+		return nil, fmt.Errorf("GetRandomBytes: got %d bytes, want %d", len(r.RandomBytes), size)
+	}
+	return r.RandomBytes, nil
+}
+
+// QuickListSecrets returns the path and expiry of every live secret, without
+// their data. The server sends them all in one reply.
+func (c *Client) QuickListSecrets(ctx context.Context) ([]*response.QuickSecret, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_QuickListSecrets{
+			QuickListSecrets: &request.QuickListSecrets{},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetQuickListSecrets()
+	if err := check("QuickListSecrets", r); err != nil {
+		return nil, err
+	}
+	return r.QuickSecrets, nil
+}
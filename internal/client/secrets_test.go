@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func noteData(content string) *request.SecretData {
+	return &request.SecretData{
+		Type: request.SecretType_NOTE,
+		Data: &request.SecretData_Note{Note: &request.Note{Content: content}},
+	}
+}
+
+func TestSecretLifecycle(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	expiry := uint64(4102444800)
+	if err := c.AddSecret(ctx, &request.AddSecret{Path: "/app/db", Expiry: &expiry, SecretData: noteData("v1")}); err != nil {
+		t.Fatalf("AddSecret: %v", err)
+	}
+	err := c.AddSecret(ctx, &request.AddSecret{Path: "/app/db", SecretData: noteData("again")})
+	if !errors.Is(err, client.ErrExists) {
+		t.Fatalf("AddSecret on a taken path = %v, want ErrExists", err)
+	}
+	if err := c.UpdateSecret(ctx, &request.UpdateSecret{Path: "/app/db", SecretData: noteData("v2")}); err != nil {
+		t.Fatalf("UpdateSecret: %v", err)
+	}
+
+	secret, err := c.GetSecret(ctx, "/app/db")
+	if err != nil {
+		t.Fatalf("GetSecret: %v", err)
+	}
+	if secret.Expiry != expiry || len(secret.Versions) != 2 {
+		t.Fatalf("GetSecret = expiry %d with %d versions, want %d with 2", secret.Expiry, len(secret.Versions), expiry)
+	}
+	if got := secret.Versions[1].SecretData.GetNote().GetContent(); got != "v2" {
+		t.Errorf("latest version holds %q, want v2", got)
+	}
+
+	if err := c.DeleteSecret(ctx, "/app/db"); err != nil {
+		t.Fatalf("DeleteSecret: %v", err)
+	}
+	if _, err := c.GetSecret(ctx, "/app/db"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetSecret after delete = %v, want ErrNotFound", err)
+	}
+	if err := c.UndeleteSecret(ctx, "/app/db"); err != nil {
+		t.Fatalf("UndeleteSecret: %v", err)
+	}
+	if secret, err := c.GetSecret(ctx, "/app/db"); err != nil || len(secret.Versions) != 2 {
+		t.Fatalf("GetSecret after undelete = %v, %v, want both versions back", secret, err)
+	}
+	if err := c.UndeleteSecret(ctx, "/app/db"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("UndeleteSecret of a live secret = %v, want ErrNotFound", err)
+	}
+	if err := c.DeleteSecret(ctx, "/app/db"); err != nil {
+		t.Fatalf("DeleteSecret: %v", err)
+	}
+	if err := c.DestroySecret(ctx, "/app/db"); err != nil {
+		t.Fatalf("DestroySecret: %v", err)
+	}
+	if srv.SecretDeleted("/app/db") {
+		t.Error("secret still kept after DestroySecret")
+	}
+}
+
+func TestQuickListSecrets(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	expiry := uint64(4102444800)
+	for _, path := range []string{"/b", "/a", "/c"} {
+		if err := c.AddSecret(ctx, &request.AddSecret{Path: path, Expiry: &expiry, SecretData: noteData(path)}); err != nil {
+			t.Fatalf("AddSecret: %v", err)
+		}
+	}
+	if err := c.DeleteSecret(ctx, "/c"); err != nil {
+		t.Fatalf("DeleteSecret: %v", err)
+	}
+	secrets, err := c.QuickListSecrets(ctx)
+	if err != nil {
+		t.Fatalf("QuickListSecrets: %v", err)
+	}
+	var paths []string
+	for _, s := range secrets {
+		if s.Expiry != expiry {
+			t.Errorf("%s has expiry %d, want %d", s.Path, s.Expiry, expiry)
+		}
+		paths = append(paths, s.Path)
+	}
+	if got := strings.Join(paths, " "); got != "/a /b" {
+		t.Errorf("QuickListSecrets = %s, want the live secrets /a /b", got)
+	}
+}
+
+func TestGetSecretCorrupt(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_GetSecret{GetSecret: &response.GetSecret{
+			Data: []byte{0xff, 0xff},
+		}}}
+	})
+	if _, err := c.GetSecret(context.Background(), "/app/db"); err == nil {
+		t.Fatal("GetSecret succeeded on corrupt data")
+	}
+}
+
+func TestGetRandomBytes(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	b, err := c.GetRandomBytes(ctx, 32, false)
+	if err != nil || len(b) != 32 {
+		t.Fatalf("GetRandomBytes = %d bytes, %v, want 32", len(b), err)
+	}
+	b, err = c.GetRandomBytes(ctx, 20, true)
+	if err != nil {
+		t.Fatalf("GetRandomBytes alphanumeric: %v", err)
+	}
+	for _, r := range string(b) {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			t.Fatalf("GetRandomBytes alphanumeric = %q, want only letters and digits", b)
+		}
+	}
+	if _, err := c.GetRandomBytes(ctx, 0, false); err == nil {
+		t.Fatal("GetRandomBytes of 0 bytes succeeded")
+	}
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Share shares acl.ObjectPath according to acl. It fails with ErrExists
+// when the object is already shared.
+func (c *Client) Share(ctx context.Context, acl *request.ACL) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_Share{
+			Share: &request.Share{
+				Acl: acl,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("Share", resp.GetShare())
+}
+
+// UpdateShare replaces the ACL of a shared object.
+func (c *Client) UpdateShare(ctx context.Context, acl *request.ACL) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_UpdateShare{
+			UpdateShare: &request.UpdateShare{
+				Acl: acl,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("UpdateShare", resp.GetUpdateShare())
+}
+
+// Unshare stops sharing the object at path.
+func (c *Client) Unshare(ctx context.Context, path string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_Unshare{
+			Unshare: &request.Unshare{
+				ObjectPath: path,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("Unshare", resp.GetUnshare())
+}
+
+// ListShares returns the ACL of the object at path. It fails with
+// ErrNotFound when the object is not shared.
+func (c *Client) ListShares(ctx context.Context, path string) (*request.ACL, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_ListShares{
+			ListShares: &request.ListShares{
+				ObjectPath: path,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetListShares()
+	if err := check("ListShares", r); err != nil {
+		return nil, err
+	}
+	var acl request.ACL
+	if err := proto.Unmarshal(r.Acl, &acl); err != nil {
+		return nil, fmt.Errorf("ListShares: decoding acl: %w", err)
+	}
+	return &acl, nil
+}
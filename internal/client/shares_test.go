@@ -0,0 +1,69 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+	"terraform-provider-izysafe/internal/ysafetest"
+)
+
+func TestShareLifecycle(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+	srv.AddUser("dev@example.com")
+	if err := c.CreateFolder(ctx, "/", "docs", nil); err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+
+	acl := &request.ACL{
+		ObjectPath: "/docs",
+		TypeOfPath: request.TypeOfPath_TFolder,
+		Actions: []*request.ActionEntry{{
+			Action:      request.Action_ALLOW,
+			Subject:     "dev@example.com",
+			SubjectType: request.SubjectType_USER,
+			OpIds:       []int32{int32(request.Op_OpGetSecret)},
+		}},
+	}
+	if err := c.Share(ctx, acl); err != nil {
+		t.Fatalf("Share: %v", err)
+	}
+	if err := c.Share(ctx, acl); !errors.Is(err, client.ErrExists) {
+		t.Fatalf("Share of a shared folder = %v, want ErrExists", err)
+	}
+	acl.Actions[0].Action = request.Action_DENY
+	if err := c.UpdateShare(ctx, acl); err != nil {
+		t.Fatalf("UpdateShare: %v", err)
+	}
+	got, err := c.ListShares(ctx, "/docs")
+	if err != nil {
+		t.Fatalf("ListShares: %v", err)
+	}
+	if got.OwnerEmail != "a@example.com" || len(got.Actions) != 1 || got.Actions[0].Action != request.Action_DENY {
+		t.Fatalf("ListShares = %v, want the updated acl owned by a@example.com", got)
+	}
+	if err := c.Unshare(ctx, "/docs"); err != nil {
+		t.Fatalf("Unshare: %v", err)
+	}
+	if _, err := c.ListShares(ctx, "/docs"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("ListShares after unshare = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListSharesCorrupt(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_ListShares{ListShares: &response.ListShares{
+			Acl: []byte{0xff, 0xff},
+		}}}
+	})
+	if _, err := c.ListShares(context.Background(), "/docs"); err == nil {
+		t.Fatal("ListShares succeeded on corrupt data")
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNoOrganization is returned by the operations on the members of the
+// organization when the signed in user is an individual user.
+var ErrNoOrganization = errors.New("the signed in user is not a member of an organization")
+
+// InviteUser invites email to join the organization of the signed in user.
+// It fails with ErrExists when email is already a member or invited.
+func (c *Client) InviteUser(ctx context.Context, email string) error {
+	if c.Organization == "" {
+		return fmt.Errorf("InviteUser: %w", ErrNoOrganization)
+	}
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_InviteUser{
+			InviteUser: &request.InviteUser{
+				Organization: c.Organization,
+				Email:        email,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("InviteUser", resp.GetInviteUser())
+}
+
+// RemoveUser removes email from the organization of the signed in user,
+// whether they joined or are still invited.
+func (c *Client) RemoveUser(ctx context.Context, email string) error {
+	if c.Organization == "" {
+		return fmt.Errorf("RemoveUser: %w", ErrNoOrganization)
+	}
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_RemoveUser{
+			RemoveUser: &request.RemoveUser{
+				Email:        email,
+				Organization: &c.Organization,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("RemoveUser", resp.GetRemoveUser())
+}
+
+// GetUser returns the account of email: a User for members and invitees,
+// or the Organization when email is the account of the organization itself.
+func (c *Client) GetUser(ctx context.Context, email string) (*response.UserMeta, error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetUser{
+			GetUser: &request.GetUser{
+				Email: email,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := resp.GetGetUser()
+	if err := check("GetUser", r); err != nil {
+		return nil, err
+	}
+	return r.User, nil
+}
+
+// SetRolesTeamsToUser replaces the roles and teams of the user email.
+func (c *Client) SetRolesTeamsToUser(ctx context.Context, email string, roles, teams []string) error {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_SetRolesTeamsToUser{
+			SetRolesTeamsToUser: &request.SetRolesTeamsToUser{
+				EmailOfUser: email,
+				NameOfRoles: roles,
+				NameOfTeams: teams,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return check("SetRolesTeamsToUser", resp.GetSetRolesTeamsToUser())
+}
+
+// GetRolesAndTeamsOfUser returns the names of the roles and teams of the
+// user email.
+func (c *Client) GetRolesAndTeamsOfUser(ctx context.Context, email string) (roles, teams []string, err error) {
+	resp, err := c.SendContext(ctx, &request.Request{
+		Operation: &request.Request_GetRolesAndTeamsOfUser{
+			GetRolesAndTeamsOfUser: &request.GetRolesAndTeamsOfUser{
+				EmailOfUser: email,
+			},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	r := resp.GetGetRolesAndTeamsOfUser()
+	if err := check("GetRolesAndTeamsOfUser", r); err != nil {
+		return nil, nil, err
+	}
+	return r.NameOfRoles, r.NameOfTeams, nil
+}
+
+// listUsersPageSize is the number of users ListUsers asks for at a time.
+const listUsersPageSize = 100
+
+// ListUsers returns every member of the organization, with the names of
+// their roles and teams. It pages through the users pageSize at a time, or
+// listUsersPageSize at a time when pageSize is 0.
+func (c *Client) ListUsers(ctx context.Context, pageSize uint32) ([]*response.UserMetaForList, error) {
+	if pageSize == 0 {
+		pageSize = listUsersPageSize
+	}
+	var users []*response.UserMetaForList
+	var token []byte
+	for {
+		resp, err := c.SendContext(ctx, &request.Request{
+			Operation: &request.Request_ListUsers{
+				ListUsers: &request.ListUsers{
+					PageTokenListUser: token,
+					PageSize:          &pageSize,
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		r := resp.GetListUsers()
+		if err := check("ListUsers", r); err != nil {
+			return nil, err
+		}
+		users = append(users, r.Users...)
+		if len(r.PageTokenListUser) == 0 {
+			return users, nil
+		}
+		var next response.PageTokenListUser
+		if err := proto.Unmarshal(r.PageTokenListUser, &next); err != nil {
+			return nil, fmt.Errorf("ListUsers: decoding page token: %w", err)
+		}
+		if next.LastSerialNumber == 0 {
+			return users, nil
+		}
+		if bytes.Equal(token, r.PageTokenListUser) || len(r.Users) == 0 {
+			// The server would answer with the same page forever.
+			return nil, fmt.Errorf("ListUsers: %w: page token did not advance", ErrUnexpectedReply)
+		}
+		token = r.PageTokenListUser
+	}
+}
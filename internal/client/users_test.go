@@ -0,0 +1,125 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRolesAndTeamsOfUser(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+	srv.AddUser("dev@example.com")
+
+	if err := c.AddRole(ctx, &request.AddRole{Name: "readers", Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+		t.Fatalf("AddRole: %v", err)
+	}
+	if err := c.AddTeam(ctx, &request.AddTeam{Name: "ops", Roles: []string{"readers"}}); err != nil {
+		t.Fatalf("AddTeam: %v", err)
+	}
+	if err := c.SetRolesTeamsToUser(ctx, "dev@example.com", []string{"readers"}, []string{"ops"}); err != nil {
+		t.Fatalf("SetRolesTeamsToUser: %v", err)
+	}
+	roles, teams, err := c.GetRolesAndTeamsOfUser(ctx, "dev@example.com")
+	if err != nil || len(roles) != 1 || roles[0] != "readers" || len(teams) != 1 || teams[0] != "ops" {
+		t.Fatalf("GetRolesAndTeamsOfUser = %v, %v, %v, want [readers] [ops]", roles, teams, err)
+	}
+	if err := c.SetRolesTeamsToUser(ctx, "dev@example.com", nil, nil); err != nil {
+		t.Fatalf("SetRolesTeamsToUser to nothing: %v", err)
+	}
+	if roles, teams, _ := c.GetRolesAndTeamsOfUser(ctx, "dev@example.com"); len(roles)+len(teams) != 0 {
+		t.Fatalf("user kept %v %v after clearing", roles, teams)
+	}
+	if _, _, err := c.GetRolesAndTeamsOfUser(ctx, "nobody@example.com"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetRolesAndTeamsOfUser of a stranger = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOrganizationMembers(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+	if c.Organization != ysafetest.Organization {
+		t.Fatalf("Organization = %q, want %q", c.Organization, ysafetest.Organization)
+	}
+
+	if err := c.InviteUser(ctx, "new@example.com"); err != nil {
+		t.Fatalf("InviteUser: %v", err)
+	}
+	if err := c.InviteUser(ctx, "new@example.com"); !errors.Is(err, client.ErrExists) {
+		t.Fatalf("InviteUser twice = %v, want ErrExists", err)
+	}
+	meta, err := c.GetUser(ctx, "new@example.com")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if u := meta.GetUserObj(); u == nil || u.Email != "new@example.com" || len(u.UserId) != 0 {
+		t.Fatalf("GetUser = %v, want an invited user without an id", meta)
+	}
+	if err := c.RemoveUser(ctx, "new@example.com"); err != nil {
+		t.Fatalf("RemoveUser: %v", err)
+	}
+	if _, err := c.GetUser(ctx, "new@example.com"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetUser after remove = %v, want ErrNotFound", err)
+	}
+
+	c.Organization = ""
+	if err := c.InviteUser(ctx, "new@example.com"); !errors.Is(err, client.ErrNoOrganization) {
+		t.Fatalf("InviteUser as an individual = %v, want ErrNoOrganization", err)
+	}
+}
+
+func TestListUsersPages(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+	for _, email := range []string{"b@example.com", "c@example.com", "d@example.com", "e@example.com"} {
+		srv.AddUser(email)
+	}
+
+	users, err := c.ListUsers(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	var emails []string
+	for _, u := range users {
+		emails = append(emails, u.Email)
+	}
+	// The account of the client is a member as well.
+	if got := strings.Join(emails, ","); got != "a@example.com,b@example.com,c@example.com,d@example.com,e@example.com" {
+		t.Fatalf("ListUsers = %s, want every user once", got)
+	}
+	pages := 0
+	for _, r := range srv.Requests() {
+		if r.Request.GetListUsers() != nil {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Fatalf("ListUsers asked for %d pages, want 3", pages)
+	}
+}
+
+func TestListUsersStuckToken(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	c := newTestClient(t, srv)
+	token, _ := proto.Marshal(&response.PageTokenListUser{LastSerialNumber: 1})
+	srv.Handle(func(string, *request.Request) *response.Response {
+		return &response.Response{Operation: &response.Response_ListUsers{ListUsers: &response.ListUsers{
+			Users:             []*response.UserMetaForList{{Email: "a@example.com"}},
+			PageTokenListUser: token,
+		}}}
+	})
+	if _, err := c.ListUsers(context.Background(), 0); !errors.Is(err, client.ErrUnexpectedReply) {
+		t.Fatalf("ListUsers with a stuck page token = %v, want ErrUnexpectedReply", err)
+	}
+}
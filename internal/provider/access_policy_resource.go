@@ -2,17 +2,13 @@ package provider
 
 import (
 	"context"
-	"encoding/binary"
 	"fmt"
 	"terraform-provider-izysafe/internal/client"
+	"time"
 
-	"terraform-provider-izysafe/internal/proto/request"
-	"terraform-provider-izysafe/internal/proto/response"
-
-	"github.com/fxamacker/cbor/v2"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"google.golang.org/protobuf/proto"
 )
 
 func resourceAccessPolicy() *schema.Resource {
@@ -21,10 +17,20 @@ func resourceAccessPolicy() *schema.Resource {
 		ReadContext:   resourceAccessPolicyRead,
 		DeleteContext: resourceAccessPolicyDelete,
 		UpdateContext: resourceAccessPolicyUpdate,
+		CustomizeDiff: resourceAccessPolicyCustomizeDiff,
+		Description: "Manages a folder in the root folder and the policy enforced on it. The server only accepts " +
+			"the policy when the folder is created and has no operation to change it, so changing `name` or a " +
+			"policy attribute fails the plan unless `allow_replace` is set.",
 
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceMyBucketImportState,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -57,119 +63,40 @@ func resourceAccessPolicy() *schema.Resource {
 				Optional:    true,
 				Description: "Time(in s) for the file to be automatically deleted after the latest change.",
 			},
+			"allow_replace": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Replace the folder when `name` or a policy attribute changes. Replacing moves the folder " +
+					"and all of its files to the trash and creates a new, empty folder. Default false, which fails " +
+					"the plan instead.",
+			},
 		},
 	}
 }
 
-func getMetaFrom(name string, client *client.Client) (response.GetMetaFromPath, diag.Diagnostics) {
-	if client == nil {
-		return response.GetMetaFromPath{}, diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
-	}
-	getMeta := request.GetMetaFromPath{
-		Path:       "/" + name,
-		Trashed:    false,
-		TypeOfPath: 0,
-	}
-	req := request.Request{
-		Operation: &request.Request_GetMetaFromPath{
-			GetMetaFromPath: &getMeta,
-		},
-	}
-	res, err := client.Send(&req)
-	if err != nil {
-		return response.GetMetaFromPath{}, diag.Errorf("Request/Response sent/recieved incorrectly" + err.Error())
-	}
-	if res == nil {
-		return response.GetMetaFromPath{}, diag.Errorf("Empty Response")
-	}
-	return *res.GetGetMetaFromPath(), nil
-}
-
 func resourceMyBucketImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	name := d.Id()
 	client := m.(*client.Client)
 	if client == nil {
 		return nil, fmt.Errorf("client is nil, please check the token and pin. contact support if the issue persists")
 	}
-	stat, err := getMetaFrom(name, client)
-	if err != nil {
-		return []*schema.ResourceData{d}, fmt.Errorf("%v", err)
+	folderMeta, err := client.GetFolder(ctx, "/"+name)
+	if isNotAFolder(err) {
+		return []*schema.ResourceData{d}, fmt.Errorf("given name is not of a folder. read folder invalid")
 	}
-
-	if stat.Status == 0 {
-		folderMeta := stat.Meta.GetFolderMeta()
-		if folderMeta == nil {
-			return []*schema.ResourceData{d}, fmt.Errorf("given name is not of a folder. read folder invalid")
-		}
+	if err == nil {
 		d.Set("name", name)
-		var policyObj request.Policy
-		policyBytes := folderMeta.Policy
-		err := proto.Unmarshal(policyBytes, &policyObj)
+		d.Set("allow_replace", false)
+		policy, err := decodePolicy(folderMeta.Policy)
 		if err != nil {
-			return []*schema.ResourceData{d}, fmt.Errorf("wrong reponse sent. read folder failed")
-		}
-		attrList := policyObj.AttrToValue
-		var keylist []string
-		for _, attr := range attrList {
-			keylist = append(keylist, attr.Attribute)
-		}
-		maxSize := 0
-		maxFileSize := 0
-		maxFileVersions := 0
-		var autrotateVersions *bool
-		var defaultTtlForFiles *uint64
-		for idx, k := range keylist {
-			if k == "max_size" {
-				err := cbor.Unmarshal(attrList[idx].GetValue(), maxSize)
-				if err != nil {
-					return []*schema.ResourceData{d}, fmt.Errorf("data corrupted. read folder fialed")
-				}
-				break
-			} else if k == "max_file_size" {
-				err := cbor.Unmarshal(attrList[idx].GetValue(), maxFileSize)
-				if err != nil {
-					return []*schema.ResourceData{d}, fmt.Errorf("data corrupted. read folder fialed")
-				}
-				break
-			} else if k == "max_file_versions" {
-				err := cbor.Unmarshal(attrList[idx].GetValue(), maxFileVersions)
-				if err != nil {
-					return []*schema.ResourceData{d}, fmt.Errorf("data corrupted. read folder fialed")
-				}
-				break
-			} else if k == "default_ttl_for_files" {
-				defaultTtlForFiles = new(uint64)
-				err := cbor.Unmarshal(attrList[idx].GetValue(), &defaultTtlForFiles)
-				if err != nil {
-					return []*schema.ResourceData{d}, fmt.Errorf("data corrupted. read folder fialed")
-				}
-				break
-			} else if k == "remove_older_versions" {
-				autrotateVersions = new(bool)
-				err := cbor.Unmarshal(attrList[idx].GetValue(), &autrotateVersions)
-				if err != nil {
-					return []*schema.ResourceData{d}, fmt.Errorf("data corrupted. read folder fialed")
-				}
-				break
-			}
-		}
-		if maxSize > 0 {
-			d.Set("max_size", maxSize)
+			return []*schema.ResourceData{d}, fmt.Errorf("data corrupted. read folder failed: %w", err)
 		}
-		if maxFileSize > 0 {
-			d.Set("max_file_size", maxFileSize)
-		}
-		if maxFileVersions > 0 {
-			d.Set("max_file_versions", maxFileVersions)
-		}
-		if autrotateVersions != nil {
-			d.Set("remove_older_versions", *autrotateVersions)
-		}
-		if defaultTtlForFiles != nil {
-			d.Set("default_ttl_for_files", *defaultTtlForFiles)
-		}
-	} else {
+		policy.setResourceData(d)
+	} else if isNotFound(err) {
 		return []*schema.ResourceData{d}, fmt.Errorf("folder doesn't exists. import failed")
+	} else {
+		return []*schema.ResourceData{d}, err
 	}
 
 	return []*schema.ResourceData{d}, nil
@@ -182,41 +109,25 @@ func resourceAccessPolicyCreate(ctx context.Context, d *schema.ResourceData, m i
 	if client == nil {
 		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
 	}
-	stat, err := getMetaFrom(name, client)
+	policy, err := policyFromResourceData(d).encode()
 	if err != nil {
-		return err
+		return diag.Errorf("Invalid policy: %s", err)
 	}
-
-	switch stat.Status {
-	case response.Status_SUCCESS:
+	_, err = client.GetMetaFromPath(ctx, "/"+name)
+	switch {
+	case err == nil:
 		return diag.Errorf("Folder already exists. Create not valid!!!")
-	case response.Status_OBJECT_NOT_FOUND:
-		createFold := request.CreateFolder{
-			Name:       name,
-			ParentPath: "/",
-			TypeOfPath: 1,
-		}
-		req := request.Request{
-			Operation: &request.Request_CreateFolder{
-				CreateFolder: &createFold,
-			},
-		}
-		resp, err := client.Send(&req)
-		if err != nil {
-			return diag.Errorf("Create Folder failed!!!")
-		}
-		if resp == nil {
-			return diag.Errorf("Create Folder failed!!!")
-		}
-		if resp.GetCreateFolder().Status != 0 {
-			return diag.Errorf("Create Folder failed!!!")
+	case isNotFound(err):
+		if err := client.CreateFolder(ctx, "/", name, policy); err != nil {
+			return errorDiags("Create Folder failed", err, cty.GetAttrPath("name"))
 		}
 	default:
-		return diag.Errorf("Backend Error with status %s. Create Folder failed!!!", stat.Status)
+		return errorDiags("Create Folder failed", err, cty.GetAttrPath("name"))
 	}
 	d.SetId(name)
 
-	return nil
+	// Read the folder back so state holds the policy the server enforces.
+	return resourceAccessPolicyRead(ctx, d, m)
 }
 
 func resourceAccessPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -225,86 +136,23 @@ func resourceAccessPolicyRead(ctx context.Context, d *schema.ResourceData, m int
 	if client == nil {
 		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
 	}
-	stat, err := getMetaFrom(name, client)
-	if err != nil {
-		return err
+	folderMeta, err := client.GetFolder(ctx, "/"+name)
+	if isNotAFolder(err) {
+		return diag.Errorf("Given name is not of a folder. Read Folder Invalid!!!")
 	}
 
-	if stat.Status == 0 {
-		folderMeta := stat.Meta.GetFolderMeta()
-		if folderMeta == nil {
-			return diag.Errorf("Given name is not of a folder. Read Folder Invalid!!!")
-		}
-		var policyObj request.Policy
-		policyBytes := folderMeta.Policy
-		err := proto.Unmarshal(policyBytes, &policyObj)
+	if err == nil {
+		policy, err := decodePolicy(folderMeta.Policy)
 		if err != nil {
-			return diag.Errorf("Wrong reponse sent. Read folder failed!!!")
-		}
-		attrList := policyObj.AttrToValue
-		var keylist []string
-		for _, attr := range attrList {
-			keylist = append(keylist, attr.Attribute)
-		}
-		maxSize := 0
-		maxFileSize := 0
-		maxFileVersions := 0
-		var autrotateVersions *bool
-		var defaultTtlForFiles *uint64
-		for idx, k := range keylist {
-			if k == "max_size" {
-				err := cbor.Unmarshal(attrList[idx].GetValue(), maxSize)
-				if err != nil {
-					return diag.Errorf("Data Corrupted. Read folder fialed!!!")
-				}
-				break
-			} else if k == "max_file_size" {
-				err := cbor.Unmarshal(attrList[idx].GetValue(), maxFileSize)
-				if err != nil {
-					return diag.Errorf("Data Corrupted. Read folder fialed!!!")
-				}
-				break
-			} else if k == "max_file_versions" {
-				err := cbor.Unmarshal(attrList[idx].GetValue(), maxFileVersions)
-				if err != nil {
-					return diag.Errorf("Data Corrupted. Read folder fialed!!!")
-				}
-				break
-			} else if k == "default_ttl_for_files" {
-				defaultTtlForFiles = new(uint64)
-				err := cbor.Unmarshal(attrList[idx].GetValue(), &defaultTtlForFiles)
-				if err != nil {
-					return diag.Errorf("Data Corrupted. Read folder fialed!!!")
-				}
-				break
-			} else if k == "remove_older_versions" {
-				autrotateVersions = new(bool)
-				err := cbor.Unmarshal(attrList[idx].GetValue(), &autrotateVersions)
-				if err != nil {
-					return diag.Errorf("Data Corrupted. Read folder fialed!!!")
-				}
-				break
-			}
-		}
-		if maxSize > 0 {
-			d.Set("max_size", maxSize)
+			return diag.Errorf("Data Corrupted. Read folder failed: %s", err)
 		}
-		if maxFileSize > 0 {
-			d.Set("max_file_size", maxFileSize)
-		}
-		if maxFileVersions > 0 {
-			d.Set("max_file_versions", maxFileVersions)
-		}
-		if autrotateVersions != nil {
-			d.Set("remove_older_versions", *autrotateVersions)
-		}
-		if defaultTtlForFiles != nil {
-			d.Set("default_ttl_for_files", *defaultTtlForFiles)
-		}
-	} else {
+		policy.setResourceData(d)
+		return policy.unknownDiags("/" + name)
+	} else if isNotFound(err) {
 		return diag.Errorf("Folder doesn't exists. Read Folder failed!!!")
+	} else {
+		return errorDiags("Read Folder failed", err, cty.GetAttrPath("name"))
 	}
-	return nil
 }
 
 func resourceAccessPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -313,128 +161,52 @@ func resourceAccessPolicyDelete(ctx context.Context, d *schema.ResourceData, m i
 	if client == nil {
 		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
 	}
-	stat, err := getMetaFrom(name, client)
+	_, err := client.GetMetaFromPath(ctx, "/"+name)
+	if isNotFound(err) {
+		return diag.Errorf("Folder doesn't exist. Destroy not valid!!!")
+	}
 	if err != nil {
-		return err
+		return errorDiags("Remove Folder failed", err, cty.GetAttrPath("name"))
 	}
-
-	if stat.Status == 0 {
-		removeFolder := request.RemoveFolder{
-			FolderFullPath: "/" + name,
-			IsPerm:         false,
-		}
-		req := request.Request{
-			Operation: &request.Request_RemoveFolder{
-				RemoveFolder: &removeFolder,
-			},
-		}
-		resp, err := client.Send(&req)
-		if err != nil {
-			return diag.Errorf("Request/Response sent/recieved incorrectly" + err.Error())
-		}
-		if resp == nil {
-			return diag.Errorf("Remove Folder failed!!!")
-		}
-		if resp.GetRemoveFolder().Status != 0 {
-			return diag.Errorf("Remove Folder failed!!!")
-		}
-
-	} else {
-		return diag.Errorf("Folder doesn't exist. Destroy not valid!!!")
+	if err := client.RemoveFolder(ctx, "/"+name, false); err != nil {
+		return errorDiags("Remove Folder failed", err, cty.GetAttrPath("name"))
 	}
 	return nil
 }
 
 func resourceAccessPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	name := d.Get("name").(string)
-	client := m.(*client.Client)
-	if client == nil {
-		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
-	}
-	stat, err := getMetaFrom(name, client)
-	if err != nil {
-		return err
-	}
-	if stat.Status != 0 {
-		return diag.Errorf("Folder doesn't exist. Update not valid!!!")
-	} else {
-		keyValMappings := []*request.KeyValMapping{}
-		if d.HasChange("max_size") {
-			if val, ok := d.GetOk("max_size"); ok {
-				intVal, ok := val.(int)
-				if !ok {
-					return diag.Errorf("unexpected type for max_size: %T (want int)", val)
-				}
-				uintVal := uint64(intVal)
-				buf := make([]byte, 8)
-				binary.BigEndian.PutUint64(buf, uintVal)
-				keyValMappings = append(keyValMappings, &request.KeyValMapping{
-					Attribute: "max_size",
-					Value:     buf,
-				})
-			}
-		}
-		if d.HasChange("max_file_size") {
-			if val, ok := d.GetOk("max_file_size"); ok {
-				intVal, ok := val.(int)
-				if !ok {
-					return diag.Errorf("unexpected type for max_size: %T (want int)", val)
-				}
-				uintVal := uint64(intVal)
-				buf := make([]byte, 8)
-				binary.BigEndian.PutUint64(buf, uintVal)
-				keyValMappings = append(keyValMappings, &request.KeyValMapping{
-					Attribute: "max_file_size",
-					Value:     buf,
-				})
-			}
-		}
-		if d.HasChange("max_file_versions") {
-			if val, ok := d.GetOk("max_file_versions"); ok {
-				intVal, ok := val.(int)
-				if !ok {
-					return diag.Errorf("unexpected type for max_size: %T (want int)", val)
-				}
-				uintVal := uint64(intVal)
-				buf := make([]byte, 8)
-				binary.BigEndian.PutUint64(buf, uintVal)
-				keyValMappings = append(keyValMappings, &request.KeyValMapping{
-					Attribute: "max_file_versions",
-					Value:     buf,
-				})
-			}
-		}
-		if d.HasChange("remove_older_versions") {
-			if v, ok := d.GetOk("remove_older_versions"); ok {
-				buf := []byte{0}
-				if v.(bool) {
-					buf[0] = 1
-				}
-				keyValMappings = append(keyValMappings, &request.KeyValMapping{
-					Attribute: "remove_older_versions",
-					Value:     buf,
-				})
-			}
-		}
-		if d.HasChange("remove_older_versions") {
-			if val, ok := d.GetOk("default_ttl_for_files"); ok {
-				intVal, ok := val.(int)
-				if !ok {
-					return diag.Errorf("unexpected type for max_size: %T (want int)", val)
-				}
-				uintVal := uint64(intVal)
-				buf := make([]byte, 8)
-				binary.BigEndian.PutUint64(buf, uintVal)
-				keyValMappings = append(keyValMappings, &request.KeyValMapping{
-					Attribute: "default_ttl_for_files",
-					Value:     buf,
-				})
+	// Only allow_replace can change in place, see
+	// resourceAccessPolicyCustomizeDiff.
+	return resourceAccessPolicyRead(ctx, d, m)
+}
+
+// resourceAccessPolicyCustomizeDiff handles changes the server cannot apply
+// to an existing folder: the policy is only accepted by CreateFolder, and
+// renames are not supported. Such a change replaces the folder when
+// allow_replace is set and fails the plan otherwise, since replacing moves
+// every file in the folder to the trash.
+func resourceAccessPolicyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	attrs := []string{"name"}
+	for _, f := range (&folderPolicy{}).fields() {
+		attrs = append(attrs, f.attr)
+	}
+	for _, attr := range attrs {
+		if !d.HasChange(attr) {
+			continue
+		}
+		if d.Get("allow_replace").(bool) {
+			if err := d.ForceNew(attr); err != nil {
+				return err
 			}
+			continue
 		}
-		_ = request.Policy{
-			AttrToValue: keyValMappings,
-		}
+		old, new := d.GetChange(attr)
+		return fmt.Errorf("%s of folder %q cannot be changed from %v to %v: the server cannot change it on an "+
+			"existing folder. Set allow_replace = true to replace the folder, which moves it and all of its files "+
+			"to the trash and creates a new, empty folder, or revert the change", attr, d.Id(), old, new)
 	}
-	d.SetId(name)
 	return nil
 }
@@ -1,12 +1,20 @@
 package provider_test
 
 import (
+	"bytes"
 	"fmt"
-	"os"
+	"reflect"
+	"regexp"
 	"testing"
 
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/fxamacker/cbor/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -14,15 +22,15 @@ var (
 )
 
 func TestAccPolicyBasic(t *testing.T) {
-	testAccPreCheckPolicy(t)
+	srv, providerConfig := testAccServer(t)
 	random := acctest.RandString(6)
 
 	resource.Test(t, resource.TestCase{
 		ProviderFactories: testAccProviderFactories,
-		CheckDestroy:      testAccCheckProjectDestroy,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccPolicyConfigBasic(fmt.Sprintf("proj_%s", random)),
+				Config: providerConfig + testAccPolicyConfigBasic(fmt.Sprintf("proj_%s", random)),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(fmt.Sprintf("ysafe_access_policy.proj_%s", random), "name", fmt.Sprintf("proj_%s", random)),
 					resource.TestCheckResourceAttrSet(fmt.Sprintf("ysafe_access_policy.proj_%s", random), "id"),
@@ -40,21 +48,13 @@ func TestAccPolicyBasic(t *testing.T) {
 func testAccPolicyConfigBasic(name string) string {
 	return fmt.Sprintf(
 		`
-		provider "izysafe" {
-		token    = "%s"
-		pin      = "%s"
-		}
-
 		resource "ysafe_access_policy" "%s" {
 		name = "%s"
 		}
-	`, os.Getenv("YSAFE_TOKEN"), os.Getenv("YSAFE_PIN"), name, name)
+	`, name, name)
 }
 
 func TestAccPolicyOneAttribute(t *testing.T) {
-	// Optional pre-flight check: bail out early if creds are missing.
-	testAccPreCheckPolicy(t)
-
 	// Random suffix to avoid name collisions between parallel tests.
 	for idx := range len(policyAttrList) {
 
@@ -99,12 +99,13 @@ func TestAccPolicyOneAttribute(t *testing.T) {
 			}
 		}
 
+		srv, providerConfig := testAccServer(t)
 		resource.Test(t, resource.TestCase{
 			ProviderFactories: testAccProviderFactories,
-			CheckDestroy:      testAccCheckProjectDestroy,
+			CheckDestroy:      testAccCheckProjectDestroy(srv),
 			Steps: []resource.TestStep{
 				{
-					Config: testAccPolicyConfigOneAttribute(fmt.Sprintf("proj_%s", random), policyAttrList[randIdx], randVal),
+					Config: providerConfig + testAccPolicyConfigOneAttribute(fmt.Sprintf("proj_%s", random), policyAttrList[randIdx], randVal),
 					Check:  resource.ComposeTestCheckFunc(checks...),
 				},
 			},
@@ -115,14 +116,161 @@ func TestAccPolicyOneAttribute(t *testing.T) {
 func testAccPolicyConfigOneAttribute(name string, attr string, value any) string {
 	return fmt.Sprintf(
 		`
-		provider "izysafe" {
-			token    = "%s"
-			pin      = "%s"
-		}
-
 		resource "ysafe_access_policy" "%s" {
 			name = "%s"
 			%s = %v
 		}
-	`, os.Getenv("YSAFE_TOKEN"), os.Getenv("YSAFE_PIN"), name, name, attr, value)
+	`, name, name, attr, value)
+}
+
+func TestAccPolicyUpdateRejected(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	name := fmt.Sprintf("proj_%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testAccPolicyConfigOneAttribute(name, "max_file_versions", 2),
+			},
+			{
+				Config:      providerConfig + testAccPolicyConfigOneAttribute(name, "max_file_versions", 3),
+				ExpectError: regexp.MustCompile(`max_file_versions of folder "` + name + `" cannot be changed from 2 to 3`),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+					resource "ysafe_access_policy" %q {
+						name              = "%s_renamed"
+						max_file_versions = 2
+					}
+				`, name, name),
+				ExpectError: regexp.MustCompile(`name of folder "` + name + `" cannot be changed`),
+			},
+		},
+	})
+}
+
+func TestAccPolicyUpdateReplaces(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	name := fmt.Sprintf("proj_%s", acctest.RandString(6))
+	config := func(versions int) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_access_policy" "test" {
+				name              = %q
+				max_file_versions = %d
+				allow_replace     = true
+			}
+		`, name, versions)
+	}
+	var uuid []byte
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(2),
+				Check: func(*terraform.State) error {
+					folder, ok := srv.Folder("/" + name)
+					if !ok {
+						return fmt.Errorf("folder %s does not exist", name)
+					}
+					uuid = folder.Uuid
+					return nil
+				},
+			},
+			{
+				// With allow_replace the folder is replaced by one created
+				// with the new policy.
+				Config: config(3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_access_policy.test", "max_file_versions", "3"),
+					testAccCheckFolderPolicy(srv, name, map[string]any{
+						"max_file_versions":     uint64(3),
+						"remove_older_versions": true,
+					}),
+					func(*terraform.State) error {
+						folder, ok := srv.Folder("/" + name)
+						if !ok {
+							return fmt.Errorf("folder %s does not exist", name)
+						}
+						if bytes.Equal(folder.Uuid, uuid) {
+							return fmt.Errorf("folder %s was not replaced", name)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccPolicyCreateSetsPolicy(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	name := fmt.Sprintf("proj_%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+					resource "ysafe_access_policy" "test" {
+						name                  = %q
+						max_size              = 1048576
+						max_file_size         = 4096
+						max_file_versions     = 2
+						remove_older_versions = false
+						default_ttl_for_files = 86400
+					}
+				`, name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFolderPolicy(srv, name, map[string]any{
+						"max_size":              uint64(1048576),
+						"max_file_size":         uint64(4096),
+						"max_file_versions":     uint64(2),
+						"remove_older_versions": false,
+						"default_ttl_for_files": uint64(86400),
+					}),
+					resource.TestCheckResourceAttr("ysafe_access_policy.test", "max_size", "1048576"),
+					resource.TestCheckResourceAttr("ysafe_access_policy.test", "remove_older_versions", "false"),
+				),
+			},
+			{
+				// Every policy attribute has to survive an import.
+				ResourceName:      "ysafe_access_policy.test",
+				ImportState:       true,
+				ImportStateId:     name,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testAccCheckFolderPolicy checks the policy the server stored for the folder
+// against want, keyed by attribute.
+func testAccCheckFolderPolicy(srv *ysafetest.Server, name string, want map[string]any) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		folder, ok := srv.Folder("/" + name)
+		if !ok {
+			return fmt.Errorf("folder %s does not exist", name)
+		}
+		var policy request.Policy
+		if err := proto.Unmarshal(folder.Policy, &policy); err != nil {
+			return err
+		}
+		got := map[string]any{}
+		for _, kv := range policy.AttrToValue {
+			var v any
+			if err := cbor.Unmarshal(kv.Value, &v); err != nil {
+				return fmt.Errorf("%s: %w", kv.Attribute, err)
+			}
+			got[kv.Attribute] = v
+		}
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("policy of folder %s is %v, want %v", name, got, want)
+		}
+		return nil
+	}
 }
@@ -5,10 +5,11 @@ import (
 	"encoding/base64"
 	"strconv"
 	"terraform-provider-izysafe/internal/client"
+	"time"
 
 	"terraform-provider-izysafe/internal/proto/request"
-	"terraform-provider-izysafe/internal/proto/response"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -22,6 +23,13 @@ func resourceAccessToken() *schema.Resource {
 		DeleteContext: resourceAccessTokenDelete,
 		UpdateContext: resourceAccessTokenUpdate,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"label": {
 				Type:        schema.TypeString,
@@ -89,32 +97,13 @@ func resourceAccessTokenCreate(ctx context.Context, d *schema.ResourceData, m in
 		}
 	}
 
-	req := &request.Request{
-		Operation: &request.Request_AddPin{
-			AddPin: &addPinReq,
-		},
-	}
-
-	var idToClientResp []byte
-	resp, err := client.Send(req)
+	addPinResponse, err := client.AddPin(ctx, &addPinReq)
 	if err != nil {
-		return diag.Errorf("Failed to send request:%v", err.Error())
-	}
-	var Data []byte
-	switch r := resp.Operation.(type) {
-	case *response.Response_AddPin:
-		addPinResponse := r.AddPin
-		if addPinResponse.Status != response.Status_SUCCESS {
-			return diag.Errorf("Failed to add pin: %v", *addPinResponse.Message)
-		}
-		Data = addPinResponse.Data
-		idToClientResp = addPinResponse.IdToClient
-	default:
-		return diag.Errorf("Unknown Operation: %v", r)
+		return errorDiags("Failed to add pin", err, cty.GetAttrPath("label"))
 	}
 	d.SetId(label)
-	d.Set("token", base64.StdEncoding.EncodeToString(Data))
-	d.Set("id_sent_to_client", base64.StdEncoding.EncodeToString(idToClientResp))
+	d.Set("token", base64.StdEncoding.EncodeToString(addPinResponse.Data))
+	d.Set("id_sent_to_client", base64.StdEncoding.EncodeToString(addPinResponse.IdToClient))
 	return nil
 }
 
@@ -149,25 +138,8 @@ func resourceAccessTokenDelete(ctx context.Context, d *schema.ResourceData, m in
 		Data:           data,
 	}
 
-	req := &request.Request{
-		Operation: &request.Request_DeletePin{
-			DeletePin: delPinReq,
-		},
-	}
-
-	resp, err := client.Send(req)
-	if err != nil {
-		return diag.Errorf("Failed to send request: %v", err.Error())
-	}
-
-	switch r := resp.Operation.(type) {
-	case *response.Response_DeletePin:
-		delPinResp := r.DeletePin
-		if delPinResp.Status != response.Status_SUCCESS {
-			return diag.Errorf("Failed to delete pin: %v", *delPinResp.Message)
-		}
-	default:
-		return diag.Errorf("Unknown Operation: %v", r)
+	if err := client.DeletePin(ctx, delPinReq); err != nil {
+		return errorDiags("Failed to delete pin", err, nil)
 	}
 
 	d.Set("token", "")
@@ -192,26 +164,8 @@ func resourceAccessTokenUpdate(ctx context.Context, d *schema.ResourceData, m in
 		PinName:        label,
 	}
 
-	req := &request.Request{
-		Operation: &request.Request_UpdatePinOps{
-			UpdatePinOps: updatePinReq,
-		},
-	}
-
-	resp, err := client.Send(req)
-	if err != nil {
-		return diag.Errorf("Failed to send request: %v", err.Error())
-	}
-
-	switch r := resp.Operation.(type) {
-	case *response.Response_UpdatePinOps:
-		updPinResp := r.UpdatePinOps
-		if updPinResp.Status != response.Status_SUCCESS {
-			return diag.Errorf("Failed to Update Pin:%v", *updPinResp.Message)
-		}
-		data = updatePinReq.Data
-	default:
-		diag.Errorf("Unknown Operation: %v", r)
+	if err := client.UpdatePinOps(ctx, updatePinReq); err != nil {
+		return errorDiags("Failed to Update Pin", err, nil)
 	}
 	d.Set("token", base64.StdEncoding.EncodeToString(data))
 	return nil
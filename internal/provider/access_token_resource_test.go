@@ -2,7 +2,6 @@ package provider_test
 
 import (
 	"fmt"
-	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -10,16 +9,16 @@ import (
 )
 
 func TestAccTokenBasic(t *testing.T) {
-	testAccPreCheckToken(t)
+	srv, providerConfig := testAccServer(t)
 	random := acctest.RandString(6)
 	randomNum := acctest.RandIntRange(0, 999999)
 	randomPin := fmt.Sprintf("%06d", randomNum)
 	resource.Test(t, resource.TestCase{
 		ProviderFactories: testAccProviderFactories,
-		CheckDestroy:      testAccCheckProjectDestroy,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
 		Steps: []resource.TestStep{
 			{
-				Config: testAccTokenConfigBasic(fmt.Sprintf("token-%s", random), randomPin),
+				Config: providerConfig + testAccTokenConfigBasic(fmt.Sprintf("token-%s", random), randomPin),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(fmt.Sprintf("ysafe_access_token.token-%s", random), "label", fmt.Sprintf("token-%s", random)),
 					resource.TestCheckResourceAttrSet(fmt.Sprintf("ysafe_access_token.token-%s", random), "token"),
@@ -36,16 +35,11 @@ func TestAccTokenBasic(t *testing.T) {
 func testAccTokenConfigBasic(name string, pin string) string {
 	return fmt.Sprintf(
 		`
-		provider "izysafe" {
-			token    = "%s"
-			pin      = "%s"
-		}
-
 		resource "ysafe_access_token" "%s" {
 			label = "%s"
 			pin   = "%s"
 		}
-	`, os.Getenv("YSAFE_TOKEN"), os.Getenv("YSAFE_PIN"), name, name, pin)
+	`, name, name, pin)
 }
 
 func TestAccPinResource_optionalAttributes(t *testing.T) {
@@ -64,12 +58,13 @@ func TestAccPinResource_optionalAttributes(t *testing.T) {
 	}
 
 	for _, tt := range tests {
+		srv, providerConfig := testAccServer(t)
 		resource.Test(t, resource.TestCase{
-			PreCheck:          func() { testAccPreCheckToken(t) },
 			ProviderFactories: testAccProviderFactories,
+			CheckDestroy:      testAccCheckProjectDestroy(srv),
 			Steps: []resource.TestStep{
 				{
-					Config: tt.config,
+					Config: providerConfig + tt.config,
 					Check:  tt.check,
 				},
 			},
@@ -79,15 +74,10 @@ func TestAccPinResource_optionalAttributes(t *testing.T) {
 
 func testAccPinResourceBase(label, pin string) string {
 	return fmt.Sprintf(`
-	provider "izysafe" {
-		token    = "%s"
-		pin      = "%s"
-	}
-
 	resource "ysafe_access_token" "test" {
 		label = "%s"
 		pin   = "%s"
-`, os.Getenv("YSAFE_TOKEN"), os.Getenv("YSAFE_PIN"), label, pin)
+`, label, pin)
 }
 
 func testAccPinResourceConfigWithExpiry(label, pin, expiry string) string {
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// statusHints tell the user what to do about failures they can fix.
+var statusHints = map[response.Status]string{
+	response.Status_AUTHENTICATION_FAILURE: "Check the token and pin configured on the provider.",
+	response.Status_ACCESS_DENIED:          "The user signed in with the provider token is not allowed to perform this operation. Check the roles and teams of the user and the operations allowed for the pin.",
+	response.Status_OBJECT_NOT_FOUND:       "The object does not exist, or was removed outside of Terraform.",
+	response.Status_OBJECT_EXISTS:          "An object with the same name already exists. Import it or choose another name.",
+	response.Status_OBJECT_LOCKED:          "The object is locked by another operation.",
+	response.Status_QUOTA_EXCEEDED:         "The storage quota of the organization is exhausted. Free up space or upgrade the plan.",
+	response.Status_STORAGE_FULL:           "The storage is full. Free up space or upgrade the plan.",
+	response.Status_STORAGE_READ_ONLY:      "The storage is read only at the moment, no changes can be made.",
+	response.Status_STORAGE_DISABLED:       "The storage is disabled for the organization. Contact support.",
+	response.Status_INVALID_REQUEST:        "The request was not accepted by the server. Check the configured values.",
+}
+
+// errorDiags turns an error returned by the client into diagnostics. summary
+// says what failed; path points at the attribute the failure is about and
+// may be nil.
+func errorDiags(summary string, err error, path cty.Path) diag.Diagnostics {
+	d := diag.Diagnostic{
+		Severity:      diag.Error,
+		Summary:       summary,
+		Detail:        err.Error(),
+		AttributePath: path,
+	}
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) {
+		d.Summary = fmt.Sprintf("%s: %s", summary, statusErr.Status)
+		d.Detail = fmt.Sprintf("The server answered %s with status %s.", statusErr.Op, statusErr.Status)
+		if statusErr.Message != "" {
+			d.Detail += " Message from the server: " + statusErr.Message
+		}
+		if hint, ok := statusHints[statusErr.Status]; ok {
+			d.Detail += "\n\n" + hint
+		}
+		if statusErr.Retryable() {
+			d.Detail += "\n\nThis is usually a temporary condition, running the operation again may succeed."
+		}
+	}
+	return diag.Diagnostics{d}
+}
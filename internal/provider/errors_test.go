@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestErrorDiags(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantSummary string
+		wantDetail  []string
+	}{
+		{
+			name:        "transport",
+			err:         fmt.Errorf("connection reset"),
+			wantSummary: "Create Folder failed",
+			wantDetail:  []string{"connection reset"},
+		},
+		{
+			name:        "access denied",
+			err:         &client.StatusError{Op: "CreateFolder", Status: response.Status_ACCESS_DENIED, Message: "no CreateFolder permission"},
+			wantSummary: "Create Folder failed: ACCESS_DENIED",
+			wantDetail:  []string{"CreateFolder", "no CreateFolder permission", "not allowed"},
+		},
+		{
+			name:        "locked",
+			err:         fmt.Errorf("wrapped: %w", &client.StatusError{Op: "CreateFolder", Status: response.Status_OBJECT_LOCKED}),
+			wantSummary: "Create Folder failed: OBJECT_LOCKED",
+			wantDetail:  []string{"locked", "temporary"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := errorDiags("Create Folder failed", tt.err, cty.GetAttrPath("name"))
+			if len(diags) != 1 || !diags.HasError() {
+				t.Fatalf("expected a single error, got %v", diags)
+			}
+			d := diags[0]
+			if d.Summary != tt.wantSummary {
+				t.Errorf("summary %q, want %q", d.Summary, tt.wantSummary)
+			}
+			for _, want := range tt.wantDetail {
+				if !strings.Contains(d.Detail, want) {
+					t.Errorf("detail %q does not mention %q", d.Detail, want)
+				}
+			}
+			if !d.AttributePath.Equals(cty.GetAttrPath("name")) {
+				t.Errorf("unexpected attribute path %v", d.AttributePath)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceFolder() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFolderRead,
+		Description: "Reads the metadata and policy of a folder, including folders managed elsewhere. The policy " +
+			"attributes are named like those of `ysafe_access_policy` and are null when the policy does not set them.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/.*[^/]$`), "must start with / and must not end with /"),
+				Description:  "Full path of the folder, such as `/docs`.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the folder.",
+			},
+			"parent_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Full path of the folder holding the folder.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hex encoded uuid of the folder.",
+			},
+			"uuid_base64": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Base64 encoded uuid of the folder.",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Email of the owner of the folder.",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the folder including all files and their versions.",
+			},
+			"creation_time": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Unix time (in s) the folder was created.",
+			},
+			"last_modified_time": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Unix time (in s) the folder was last changed.",
+			},
+			"current_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hex encoded id of the current version of the folder.",
+			},
+			"max_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum size of the folder including all files and their versions.",
+			},
+			"max_file_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum size of file that can be uploaded in the folder.",
+			},
+			"max_file_versions": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of previous versions of each file stored in history.",
+			},
+			"remove_older_versions": {
+				Type:     schema.TypeBool,
+				Computed: true,
+				Description: "Whether older versions are removed as new versions are uploaded. When the policy does " +
+					"not set it, it is null and the server removes them.",
+			},
+			"default_ttl_for_files": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Time (in s) for a file to be automatically deleted after the latest change.",
+			},
+		},
+	}
+}
+
+func dataSourceFolderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	path := d.Get("path").(string)
+	folder, err := client.GetFolder(ctx, path)
+	if isNotAFolder(err) {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Read Folder failed",
+			Detail:        path + " is a file, not a folder.",
+			AttributePath: cty.GetAttrPath("path"),
+		}}
+	}
+	if err != nil {
+		return errorDiags("Read Folder failed", err, cty.GetAttrPath("path"))
+	}
+	policy, err := decodePolicy(folder.Policy)
+	if err != nil {
+		return diag.Errorf("Data Corrupted. Read folder failed: %s", err)
+	}
+
+	d.SetId(path)
+	d.Set("name", folder.Name)
+	d.Set("parent_path", folder.ParentFolder)
+	d.Set("uuid", hex.EncodeToString(folder.Uuid))
+	d.Set("uuid_base64", base64.StdEncoding.EncodeToString(folder.Uuid))
+	d.Set("owner", folder.Owner)
+	d.Set("size", int(folder.Size))
+	d.Set("creation_time", int(folder.CreationDate))
+	d.Set("last_modified_time", int(folder.LastModifiedDate))
+	d.Set("current_version", hex.EncodeToString(folder.CurrentVersion))
+	policy.setDataSourceData(d)
+	return policy.unknownDiags(path)
+}
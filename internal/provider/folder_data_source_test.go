@@ -0,0 +1,113 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccFolderDataSource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	name := fmt.Sprintf("proj_%s", acctest.RandString(6))
+	c := testAccClient(t, srv)
+	if err := c.CreateFolder(context.Background(), "/", "plain", nil); err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	// A policy attribute the provider does not know only draws a warning.
+	future := &request.Policy{AttrToValue: []*request.KeyValMapping{
+		{Attribute: "max_size", Value: []byte{0x01}},
+		{Attribute: "min_size", Value: []byte{0x01}},
+	}}
+	if err := c.CreateFolder(context.Background(), "/", "future", future); err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+					resource "ysafe_access_policy" "test" {
+						name                  = %q
+						max_file_versions     = 2
+						remove_older_versions = false
+					}
+
+					data "ysafe_folder" "test" {
+						path = "/${ysafe_access_policy.test.name}"
+					}
+
+					data "ysafe_folder" "plain" {
+						path = "/plain"
+					}
+
+					data "ysafe_folder" "future" {
+						path = "/future"
+					}
+				`, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ysafe_folder.test", "name", name),
+					resource.TestCheckResourceAttr("data.ysafe_folder.test", "parent_path", "/"),
+					resource.TestCheckResourceAttr("data.ysafe_folder.test", "owner", testAccEmail),
+					resource.TestCheckResourceAttr("data.ysafe_folder.test", "size", "0"),
+					resource.TestCheckResourceAttrSet("data.ysafe_folder.test", "creation_time"),
+					resource.TestCheckResourceAttrSet("data.ysafe_folder.test", "last_modified_time"),
+					testAccCheckFolderUuid(srv, "data.ysafe_folder.test", "/"+name),
+					resource.TestCheckResourceAttr("data.ysafe_folder.test", "max_file_versions", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_folder.test", "remove_older_versions", "false"),
+					resource.TestCheckNoResourceAttr("data.ysafe_folder.test", "max_size"),
+					resource.TestCheckNoResourceAttr("data.ysafe_folder.test", "max_file_size"),
+					resource.TestCheckNoResourceAttr("data.ysafe_folder.test", "default_ttl_for_files"),
+					resource.TestCheckResourceAttr("data.ysafe_folder.plain", "name", "plain"),
+					resource.TestCheckNoResourceAttr("data.ysafe_folder.plain", "max_file_versions"),
+					resource.TestCheckNoResourceAttr("data.ysafe_folder.plain", "remove_older_versions"),
+					resource.TestCheckResourceAttr("data.ysafe_folder.future", "max_size", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFolderDataSourceNotFound(t *testing.T) {
+	_, providerConfig := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					data "ysafe_folder" "test" {
+						path = "/missing"
+					}
+				`,
+				ExpectError: regexp.MustCompile("Read Folder failed"),
+			},
+		},
+	})
+}
+
+// testAccCheckFolderUuid checks both encodings of the uuid against the one
+// the server stored for the folder.
+func testAccCheckFolderUuid(srv *ysafetest.Server, name, path string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		folder, ok := srv.Folder(path)
+		if !ok {
+			return fmt.Errorf("folder %s does not exist", path)
+		}
+		return resource.ComposeTestCheckFunc(
+			resource.TestCheckResourceAttr(name, "uuid", hex.EncodeToString(folder.Uuid)),
+			resource.TestCheckResourceAttr(name, "uuid_base64", base64.StdEncoding.EncodeToString(folder.Uuid)),
+		)(s)
+	}
+}
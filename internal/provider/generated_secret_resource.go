@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// generatedSecretInputs are the arguments whose change generates a new value.
+var generatedSecretInputs = []string{"length", "format", "keepers"}
+
+func resourceGeneratedSecret() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGeneratedSecretCreate,
+		ReadContext:   resourceGeneratedSecretRead,
+		UpdateContext: resourceGeneratedSecretUpdate,
+		DeleteContext: resourceSecretDelete,
+		CustomizeDiff: resourceGeneratedSecretCustomizeDiff,
+		Description: "Manages a key secret whose value is generated by the server, so that it never has to be " +
+			"written in the configuration. Changing `length`, `format` or `keepers`, or reaching " +
+			"`rotate_interval`, stores a newly generated value as a new version of the secret.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSecretImportState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSecretPath,
+				Description:  "Path of the secret, starting with `/`.",
+			},
+			"length": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of bytes, or characters for the `alphanumeric` format, to generate.",
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      keyFormatRandom,
+				ValidateFunc: validation.StringInSlice([]string{keyFormatRandom, keyFormatAlphanumeric}, false),
+				Description:  "`random` for arbitrary bytes, `alphanumeric` for letters and digits only. Default `random`.",
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that generate a new value when any of them changes.",
+			},
+			"expiry": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Unix time (in s) after which the secret is reported as expired.",
+			},
+			"rotate_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description: "Time (in s) after which a new value is generated. Once it has elapsed since " +
+					"`last_rotated_time`, the next plan rotates the secret. Changing the interval replaces the " +
+					"secret, which requires `deletion_mode` to be `destroy`.",
+			},
+			"deletion_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      deletionModeSoft,
+				ValidateFunc: validation.StringInSlice([]string{deletionModeSoft, deletionModeDestroy}, false),
+				Description: "What destroying the resource does to the secret. `soft` deletes it so that it can be " +
+					"recovered; `destroy` removes it and all of its versions for good. Default `soft`.",
+			},
+			"restore_deleted": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				Description: "When a soft deleted secret already uses `path`, restore it with all of its versions " +
+					"and store the generated value as a new version instead of failing. Default true.",
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated value. Base64 encoded when `format` is `random`, plain text when it is `alphanumeric`.",
+			},
+			"last_rotated_time": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Unix time (in s) the latest version of the secret was stored.",
+			},
+			"is_expired": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the expiry of the secret has passed.",
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the latest version of the secret.",
+			},
+		},
+	}
+}
+
+// generateSecretData asks the server for a new value matching length and
+// format and wraps it as a key secret.
+func generateSecretData(ctx context.Context, c *client.Client, d *schema.ResourceData) (*request.SecretData, error) {
+	length := d.Get("length").(int)
+	alphanumeric := d.Get("format").(string) == keyFormatAlphanumeric
+	b, err := c.GetRandomBytes(ctx, uint32(length), alphanumeric)
+	if err != nil {
+		return nil, err
+	}
+	size := int32(length)
+	key := &request.Key{MinSize: &size, MaxSize: &size}
+	if alphanumeric {
+		key.Format = &request.Key_Alphanumeric{Alphanumeric: b}
+	} else {
+		key.Format = &request.Key_Random{Random: b}
+	}
+	return &request.SecretData{
+		Type: request.SecretType_KEYS,
+		Data: &request.SecretData_Key{Key: key},
+	}, nil
+}
+
+func resourceGeneratedSecretCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	path := d.Get("path").(string)
+	data, err := generateSecretData(ctx, client, d)
+	if err != nil {
+		return errorDiags("Generate Secret failed", err, cty.GetAttrPath("length"))
+	}
+	req := &request.AddSecret{
+		Path:       path,
+		SecretData: data,
+	}
+	if v, ok := d.GetOk("expiry"); ok {
+		expiry := uint64(v.(int))
+		req.Expiry = &expiry
+	}
+	if v, ok := d.GetOk("rotate_interval"); ok {
+		interval := uint64(v.(int))
+		req.RotateInterval = &interval
+	}
+	err = client.AddSecret(ctx, req)
+	if isExists(err) && d.Get("restore_deleted").(bool) {
+		return resourceSecretRestore(ctx, d, m, req, err, resourceGeneratedSecretRead)
+	}
+	if err != nil {
+		return errorDiags("Add Secret failed", err, cty.GetAttrPath("path"))
+	}
+	d.SetId(path)
+
+	return resourceGeneratedSecretRead(ctx, d, m)
+}
+
+func resourceGeneratedSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	secret, err := client.GetSecret(ctx, d.Id())
+	if isNotFound(err) {
+		// Deleted outside of Terraform; plan to create it again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read Secret failed", err, cty.GetAttrPath("path"))
+	}
+	version := latestSecretVersion(secret)
+	if version == nil {
+		return diag.Errorf("Secret %s has no versions. Read Secret failed!!!", d.Id())
+	}
+	switch f := version.SecretData.GetKey().GetFormat().(type) {
+	case *response.Key_Alphanumeric:
+		d.Set("format", keyFormatAlphanumeric)
+		d.Set("length", len(f.Alphanumeric))
+		d.Set("value", string(f.Alphanumeric))
+	case *response.Key_Random:
+		d.Set("format", keyFormatRandom)
+		d.Set("length", len(f.Random))
+		d.Set("value", base64.StdEncoding.EncodeToString(f.Random))
+	default:
+		// Replaced by something other than a key outside of Terraform.
+		// Clearing format makes the next plan generate a new value.
+		d.Set("format", "")
+		d.Set("value", "")
+	}
+	d.Set("path", secret.Path)
+	setOptionalUint(d, "expiry", secret.Expiry)
+	setOptionalUint(d, "rotate_interval", secret.RotateInterval)
+	d.Set("last_rotated_time", int(secret.LastRotatedTime))
+	d.Set("is_expired", secret.IsExpired)
+	d.Set("version", int(version.Id))
+	return nil
+}
+
+func resourceGeneratedSecretUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	req := &request.UpdateSecret{
+		Path: d.Id(),
+	}
+	// A planned rotation generates a new value as well.
+	if d.HasChanges(generatedSecretInputs...) || d.HasChange("last_rotated_time") {
+		data, err := generateSecretData(ctx, client, d)
+		if err != nil {
+			return errorDiags("Generate Secret failed", err, cty.GetAttrPath("length"))
+		}
+		req.SecretData = data
+	}
+	if d.HasChange("expiry") {
+		// Zero clears the expiry.
+		expiry := uint64(d.Get("expiry").(int))
+		req.Expiry = &expiry
+	}
+	if req.SecretData != nil || req.Expiry != nil {
+		if err := client.UpdateSecret(ctx, req); err != nil {
+			return errorDiags("Update Secret failed", err, cty.GetAttrPath("path"))
+		}
+	}
+	return resourceGeneratedSecretRead(ctx, d, m)
+}
+
+// resourceGeneratedSecretCustomizeDiff rejects interval changes that cannot
+// be applied, plans a new value when the secret is due for rotation and
+// marks what an update will recompute.
+func resourceGeneratedSecretCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if err := checkRotateIntervalChange(d); err != nil {
+		return err
+	}
+	rotate := rotationDue(d.Get("rotate_interval").(int), d.Get("last_rotated_time").(int), time.Now())
+	if rotate || d.HasChanges(generatedSecretInputs...) {
+		d.SetNewComputed("value")
+		d.SetNewComputed("version")
+		d.SetNewComputed("last_rotated_time")
+	}
+	if d.HasChange("expiry") {
+		d.SetNewComputed("is_expired")
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package provider_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGeneratedSecretBasic(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := func(format, rev string) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_generated_secret" "test" {
+				path   = "/app/session_key"
+				length = 32
+				format = %q
+
+				keepers = {
+					rev = %q
+				}
+			}
+		`, format, rev)
+	}
+	var first string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config("random", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "version", "1"),
+					testAccCheckGeneratedSecret(srv, "/app/session_key", &first),
+					resource.TestCheckResourceAttrWith("ysafe_generated_secret.test", "value", func(v string) error {
+						if b, err := base64.StdEncoding.DecodeString(v); err != nil || len(b) != 32 {
+							return fmt.Errorf("value %q is not 32 base64 encoded bytes", v)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// Changing a keeper stores a new value as the next version.
+				Config: config("random", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "version", "2"),
+					testAccCheckSecretVersions(srv, "/app/session_key", 2),
+					resource.TestCheckResourceAttrWith("ysafe_generated_secret.test", "value", func(v string) error {
+						if v == first {
+							return fmt.Errorf("value was not regenerated")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: config("alphanumeric", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "version", "3"),
+					resource.TestMatchResourceAttr("ysafe_generated_secret.test", "value", regexp.MustCompile(`^[A-Za-z0-9]{32}$`)),
+				),
+			},
+			{
+				ResourceName:            "ysafe_generated_secret.test",
+				ImportState:             true,
+				ImportStateId:           "/app/session_key",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"keepers"},
+			},
+		},
+	})
+}
+
+func TestAccGeneratedSecretDrift(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := providerConfig + `
+		resource "ysafe_generated_secret" "test" {
+			path   = "/app/token"
+			length = 24
+			format = "alphanumeric"
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// Overwritten with a note outside of Terraform: a new value
+				// is generated.
+				PreConfig: func() { testAccUpdateSecret(t, srv, "/app/token", "not a key") },
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "version", "3"),
+					resource.TestMatchResourceAttr("ysafe_generated_secret.test", "value", regexp.MustCompile(`^[A-Za-z0-9]{24}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGeneratedSecretRotation(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := providerConfig + `
+		resource "ysafe_generated_secret" "test" {
+			path            = "/app/rotated"
+			length          = 16
+			rotate_interval = 3600
+		}
+	`
+	var first string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "rotate_interval", "3600"),
+					resource.TestCheckResourceAttrSet("ysafe_generated_secret.test", "last_rotated_time"),
+					testAccCheckGeneratedSecret(srv, "/app/rotated", &first),
+				),
+			},
+			{
+				// Within the interval nothing changes.
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				PreConfig: func() { srv.AgeSecret("/app/rotated", 2*time.Hour) },
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "version", "2"),
+					resource.TestCheckResourceAttrWith("ysafe_generated_secret.test", "value", func(v string) error {
+						if v == first {
+							return fmt.Errorf("value was not rotated")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: providerConfig + `
+					resource "ysafe_generated_secret" "test" {
+						path            = "/app/rotated"
+						length          = 16
+						rotate_interval = 60
+					}
+				`,
+				ExpectError: regexp.MustCompile(`cannot be changed from 3600 to 60 while deletion_mode is "soft"`),
+			},
+			{
+				Config: providerConfig + `
+					resource "ysafe_generated_secret" "test" {
+						path            = "/app/rotated"
+						length          = 16
+						rotate_interval = 3600
+						deletion_mode   = "destroy"
+					}
+				`,
+			},
+			{
+				// Changing the interval replaces the secret.
+				Config: providerConfig + `
+					resource "ysafe_generated_secret" "test" {
+						path            = "/app/rotated"
+						length          = 16
+						rotate_interval = 60
+						deletion_mode   = "destroy"
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "rotate_interval", "60"),
+					resource.TestCheckResourceAttr("ysafe_generated_secret.test", "version", "1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckGeneratedSecret checks that the value in state is the one the
+// server stored and saves it to value.
+func testAccCheckGeneratedSecret(srv *ysafetest.Server, path string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["ysafe_generated_secret.test"]
+		if !ok {
+			return fmt.Errorf("ysafe_generated_secret.test not found in state")
+		}
+		secret, ok := srv.Secret(path)
+		if !ok {
+			return fmt.Errorf("secret %s not found", path)
+		}
+		stored := secret.Versions[len(secret.Versions)-1].SecretData.GetKey().GetRandom()
+		if got := rs.Primary.Attributes["value"]; got != base64.StdEncoding.EncodeToString(stored) {
+			return fmt.Errorf("value in state does not match the stored key")
+		}
+		*value = rs.Primary.Attributes["value"]
+		return nil
+	}
+}
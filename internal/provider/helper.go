@@ -1,10 +1,31 @@
 package provider
 
 import (
+	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
+
+	"terraform-provider-izysafe/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+func isNotFound(err error) bool {
+	return errors.Is(err, client.ErrNotFound)
+}
+
+func isExists(err error) bool {
+	return errors.Is(err, client.ErrExists)
+}
+
+func isNotAFolder(err error) bool {
+	return errors.Is(err, client.ErrNotAFolder)
+}
+
 func ValidPin(val interface{}, key string) (warns []string, errs []error) {
 	pin, ok := val.(string)
 	if !ok {
@@ -42,3 +63,122 @@ func ValidateNumericString(val interface{}, key string) (warns []string, errs []
 	}
 	return
 }
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setOptionalUint stores v in an optional integer attribute where the server
+// uses zero for "not set". Zero is only written when state has a value, so
+// an attribute missing from the configuration stays null.
+func setOptionalUint(d *schema.ResourceData, key string, v uint64) {
+	var p *uint64
+	if v != 0 {
+		p = &v
+	}
+	setOptionalUintPtr(d, key, p)
+}
+
+// setOptionalUintPtr is setOptionalUint for values where nil means "not
+// set" and zero is a value of its own.
+func setOptionalUintPtr(d *schema.ResourceData, key string, v *uint64) {
+	if v != nil {
+		d.Set(key, int(*v))
+	} else if _, ok := d.GetOk(key); ok {
+		d.Set(key, 0)
+	}
+}
+
+// checkRolesExist returns an error naming the roles that do not exist.
+func checkRolesExist(ctx context.Context, c *client.Client, roles []string) error {
+	return checkNamesExist(ctx, "role", roles, c.ListRoles)
+}
+
+// checkTeamsExist returns an error naming the teams that do not exist.
+func checkTeamsExist(ctx context.Context, c *client.Client, teams []string) error {
+	return checkNamesExist(ctx, "team", teams, c.ListTeams)
+}
+
+// checkNamesExist returns an error naming the objects of the given kind
+// that list does not return.
+func checkNamesExist(ctx context.Context, kind string, names []string, list func(context.Context) ([]string, error)) error {
+	if len(names) == 0 {
+		return nil
+	}
+	existing, err := list(ctx)
+	if err != nil {
+		return fmt.Errorf("checking that the %ss exist: %w", kind, err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
+	var missing []string
+	for _, name := range names {
+		if !known[name] {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+	}
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%s %s does not exist. Create it first, or reference a %s managed in the same "+
+			"configuration by id, such as ysafe_%s.example.id, so that the check waits until it is created",
+			kind, missing[0], kind, kind)
+	}
+	return fmt.Errorf("%ss %s do not exist. Create them first, or reference %ss managed in the same "+
+		"configuration by id, such as ysafe_%s.example.id, so that the check waits until they are created",
+		kind, strings.Join(missing, ", "), kind, kind)
+}
+
+func expandStringSet(s *schema.Set) []string {
+	list := make([]string, 0, s.Len())
+	for _, v := range s.List() {
+		list = append(list, v.(string))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// roleNamesByUUID maps the uuid of every role to its name. The server only
+// offers to list role names, so every role is fetched to learn its uuid.
+func roleNamesByUUID(ctx context.Context, c *client.Client) (map[string]string, error) {
+	names, err := c.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[string]string, len(names))
+	for _, name := range names {
+		role, err := c.GetRole(ctx, name)
+		if isNotFound(err) {
+			// Removed since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		byUUID[string(role.Uuid)] = role.Name
+	}
+	return byUUID, nil
+}
+
+// roleNames resolves role uuids to sorted role names. A uuid without a role,
+// left behind by a removed role, is returned hex encoded.
+func roleNames(byUUID map[string]string, uuids [][]byte) []string {
+	roles := make([]string, 0, len(uuids))
+	for _, uuid := range uuids {
+		if name, ok := byUUID[string(uuid)]; ok {
+			roles = append(roles, name)
+		} else {
+			roles = append(roles, hex.EncodeToString(uuid))
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
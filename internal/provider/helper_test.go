@@ -1,88 +1,108 @@
 package provider_test
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"terraform-provider-izysafe/internal/client"
-	"terraform-provider-izysafe/internal/provider"
+	"strings"
 	"testing"
 
-	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/provider"
+	"terraform-provider-izysafe/internal/ysafetest"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+const (
+	testAccEmail = "owner@example.com"
+	testAccPin   = "123456"
+)
+
 var testAccProviderFactories = map[string]func() (*schema.Provider, error){
 	"ysafe": func() (*schema.Provider, error) {
 		return provider.Provider(), nil
 	},
 }
 
-func testAccPreCheckPolicy(t *testing.T) {
-	if v := os.Getenv("YSAFE_TOKEN"); v == "" {
-		t.Skip("YSAFE_TOKEN env var must be set for acceptance tests")
-	}
-	if v := os.Getenv("YSAFE_PIN"); v == "" {
-		t.Skip("YSAFE_PIN env var must be set for acceptance tests")
-	}
-}
-
-func testAccPreCheckToken(t *testing.T) {
-	if v := os.Getenv("YSAFE_TOKEN"); v == "" {
-		t.Skip("YSAFE_TOKEN env var must be set for acceptance tests")
-	}
-	if v := os.Getenv("YSAFE_PIN"); v == "" {
-		t.Skip("YSAFE_PIN env var must be set for acceptance tests")
-	}
+// testAccServer starts a fake ysafe server for one acceptance test and
+// returns it together with a provider block that signs in to it.
+func testAccServer(t *testing.T) (*ysafetest.Server, string) {
+	srv := ysafetest.NewServer(t)
+	token := srv.AddAccount(testAccEmail, testAccPin)
+	return srv, fmt.Sprintf(`
+		provider "ysafe" {
+			endpoint    = %q
+			ca_cert_pem = %q
+			token       = %q
+			pin         = %q
+		}
+	`, srv.URL, srv.CACertPEM(), token, testAccPin)
 }
 
-func verifyDestroyFolder(name string, client *client.Client) bool {
-	getMeta := request.GetMetaFromPath{
-		Path:       "/" + name,
-		Trashed:    false,
-		TypeOfPath: 0,
-	}
-	req := request.Request{
-		Operation: &request.Request_GetMetaFromPath{
-			GetMetaFromPath: &getMeta,
-		},
-	}
-	res, err := client.Send(&req)
+// testAccClient signs in to srv as the account of the test, for changing
+// objects outside of Terraform.
+func testAccClient(t *testing.T, srv *ysafetest.Server) *client.Client {
+	t.Helper()
+	token := srv.AddAccount(testAccEmail, testAccPin)
+	c, err := client.New(context.Background(), client.Config{
+		Endpoint:  srv.URL,
+		Token:     token,
+		Pin:       testAccPin,
+		CACertPEM: []byte(srv.CACertPEM()),
+	})
 	if err != nil {
-		return true
+		t.Fatalf("signing in to the test server: %v", err)
 	}
-	if res == nil {
-		return true
-	}
-	if res.GetGetMetaFromPath().Status == 0 {
-		return true
-	}
-	return false
+	return c
 }
 
-func verifyDestroyPin() bool {
-
-	return false
-}
-
-func testAccCheckProjectDestroy(s *terraform.State) error {
-	// Iterate over resources left in state; confirm they are really gone
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "ysafe_access_policy" && rs.Type != "ysafe_access_token" {
-			continue
-		}
-		endpoint := "wss://files.ysafe.io:5577"
-		pin := os.Getenv("YSAFE_PIN")
-		token := os.Getenv("YSAFE_TOKEN")
-		name := rs.Primary.ID
-		client := client.GetClient(token, endpoint, pin)
-		if verifyDestroyFolder(name, client) {
-			return fmt.Errorf("resource %s not destroyed.", name)
-		}
-		if verifyDestroyPin() {
-
+func testAccCheckProjectDestroy(srv *ysafetest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		// Iterate over resources left in state; confirm they are really gone
+		for _, rs := range s.RootModule().Resources {
+			name := rs.Primary.ID
+			switch rs.Type {
+			case "ysafe_access_policy":
+				if _, ok := srv.Folder("/" + name); ok {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_access_token":
+				if srv.HasPin(testAccEmail, name) {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_secret", "ysafe_generated_secret":
+				if _, ok := srv.Secret(name); ok {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_role":
+				if _, ok := srv.Role(name); ok {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_team":
+				if _, ok := srv.Team(name); ok {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_organization_user":
+				if exists, _ := srv.User(name); exists {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_share":
+				if _, ok := srv.Share(name); ok {
+					return fmt.Errorf("resource %s not destroyed.", name)
+				}
+			case "ysafe_user_assignment":
+				roles, teams, _ := srv.UserRolesAndTeams(name)
+				for _, v := range append(roles, teams...) {
+					for key, attr := range rs.Primary.Attributes {
+						if (strings.HasPrefix(key, "roles.") || strings.HasPrefix(key, "teams.")) && attr == v {
+							return fmt.Errorf("resource %s not destroyed: user still has %s.", name, v)
+						}
+					}
+				}
+			}
 		}
+		return nil
 	}
-	return nil
 }
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	userStatusActive  = "active"
+	userStatusInvited = "invited"
+)
+
+func resourceOrganizationUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOrganizationUserCreate,
+		ReadContext:   resourceOrganizationUserRead,
+		UpdateContext: resourceOrganizationUserUpdate,
+		DeleteContext: resourceOrganizationUserDelete,
+		Description: "Manages a member of the organization of the signed in user. Creating the resource invites " +
+			"the user, destroying it removes them from the organization. A user removed outside of Terraform is " +
+			"invited again.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOrganizationUserImportState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"email": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Email of the user.",
+			},
+			"prevent_destroy_if_owner": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				Description: "Fail to destroy the resource when the user owns the organization, rather than asking " +
+					"the server to remove the owner. Default true.",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the organization.",
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "`invited` until the user signed up through the invitation, then `active`. The owner " +
+					"of the organization is `active`.",
+			},
+			"is_owner": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user owns the organization.",
+			},
+		},
+	}
+}
+
+func resourceOrganizationUserImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("email", d.Id())
+	d.Set("prevent_destroy_if_owner", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceOrganizationUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	email := d.Get("email").(string)
+	if err := client.InviteUser(ctx, email); err != nil {
+		return errorDiags("Invite User failed", err, cty.GetAttrPath("email"))
+	}
+	d.SetId(email)
+
+	return resourceOrganizationUserRead(ctx, d, m)
+}
+
+func resourceOrganizationUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	meta, err := client.GetUser(ctx, d.Id())
+	if isNotFound(err) {
+		// Removed outside of Terraform; plan to invite them again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read User failed", err, cty.GetAttrPath("email"))
+	}
+	d.Set("email", d.Id())
+	if org := meta.GetOrganizationObj(); org != nil {
+		d.Set("organization", org.Name)
+		d.Set("status", userStatusActive)
+		d.Set("is_owner", true)
+		return nil
+	}
+	user := meta.GetUserObj()
+	if user == nil || user.Organization != client.Organization {
+		// Left the organization, and possibly kept an account of their own.
+		d.SetId("")
+		return nil
+	}
+	status := userStatusActive
+	if len(user.UserId) == 0 {
+		status = userStatusInvited
+	}
+	d.Set("organization", user.Organization)
+	d.Set("status", status)
+	d.Set("is_owner", false)
+	return nil
+}
+
+func resourceOrganizationUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Only prevent_destroy_if_owner can change in place, and it is only
+	// consulted on destroy.
+	return resourceOrganizationUserRead(ctx, d, m)
+}
+
+func resourceOrganizationUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	if d.Get("prevent_destroy_if_owner").(bool) {
+		meta, err := client.GetUser(ctx, d.Id())
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errorDiags("Remove User failed", err, cty.GetAttrPath("email"))
+		}
+		if meta.GetOrganizationObj() != nil {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Remove User refused",
+				Detail: d.Id() + " owns the organization and prevent_destroy_if_owner is set. Set " +
+					"prevent_destroy_if_owner to false and apply first to remove the owner anyway, or remove the " +
+					"resource from the state with `terraform state rm` to stop managing the user.",
+				AttributePath: cty.GetAttrPath("prevent_destroy_if_owner"),
+			}}
+		}
+	}
+	err := client.RemoveUser(ctx, d.Id())
+	if err != nil && !isNotFound(err) {
+		return errorDiags("Remove User failed", err, cty.GetAttrPath("email"))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccOrganizationUserBasic(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := providerConfig + `
+		resource "ysafe_organization_user" "test" {
+			email = "new@example.com"
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "id", "new@example.com"),
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "organization", ysafetest.Organization),
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "status", "invited"),
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "is_owner", "false"),
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "prevent_destroy_if_owner", "true"),
+				),
+			},
+			{
+				PreConfig: func() { srv.AcceptInvitation("new@example.com") },
+				Config:    config,
+				Check:     resource.TestCheckResourceAttr("ysafe_organization_user.test", "status", "active"),
+			},
+			{
+				ResourceName:      "ysafe_organization_user.test",
+				ImportState:       true,
+				ImportStateId:     "new@example.com",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccOrganizationUserRemoved(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	config := providerConfig + `
+		resource "ysafe_organization_user" "test" {
+			email = "new@example.com"
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// A user removed outside of Terraform is invited again.
+				PreConfig: func() {
+					srv.AcceptInvitation("new@example.com")
+					if err := c.RemoveUser(context.Background(), "new@example.com"); err != nil {
+						t.Fatalf("RemoveUser: %v", err)
+					}
+				},
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("ysafe_organization_user.test", "status", "invited"),
+			},
+		},
+	})
+}
+
+func TestAccOrganizationUserOwner(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	const owner = "boss@example.com"
+	srv.SetOwner(owner)
+	config := func(prevent bool) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_organization_user" "test" {
+				email                    = %q
+				prevent_destroy_if_owner = %t
+			}
+		`, owner, prevent)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config:             config(true),
+				ResourceName:       "ysafe_organization_user.test",
+				ImportState:        true,
+				ImportStateId:      owner,
+				ImportStatePersist: true,
+			},
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "is_owner", "true"),
+					resource.TestCheckResourceAttr("ysafe_organization_user.test", "status", "active"),
+				),
+			},
+			{
+				Config:      config(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`owns the organization and prevent_destroy_if_owner is set`),
+			},
+			{
+				// Without the safeguard the removal reaches the server, which
+				// refuses to remove the owner.
+				Config:      config(false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`RemoveUser with status ACCESS_DENIED`),
+			},
+			{
+				// Once the ownership moved on the user is gone from the
+				// organization and is invited again.
+				PreConfig: func() { srv.SetOwner("") },
+				Config:    config(false),
+				Check:     resource.TestCheckResourceAttr("ysafe_organization_user.test", "status", "invited"),
+			},
+		},
+	})
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"terraform-provider-izysafe/internal/proto/request"
+)
+
+// trimOpPrefix turns the name of a request.Op into a permission name such as
+// GetSecret or CreateFolder. The prefix is spelled "OP" for OPDeletePin.
+func trimOpPrefix(name string) string {
+	if len(name) > 2 && strings.EqualFold(name[:2], "Op") {
+		return name[2:]
+	}
+	return name
+}
+
+// permissionOps maps every permission name to its operation.
+var permissionOps = func() map[string]request.Op {
+	ops := make(map[string]request.Op, len(request.Op_value))
+	for name, v := range request.Op_value {
+		ops[trimOpPrefix(name)] = request.Op(v)
+	}
+	return ops
+}()
+
+// permissionName returns the name of op, or its number when this version of
+// the provider does not know it.
+func permissionName(op request.Op) string {
+	if name, ok := request.Op_name[int32(op)]; ok {
+		return trimOpPrefix(name)
+	}
+	return fmt.Sprint(int32(op))
+}
+
+func validatePermission(val interface{}, key string) (warns []string, errs []error) {
+	name, ok := val.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %q to be a string", key))
+		return
+	}
+	if _, ok := permissionOps[name]; ok {
+		return
+	}
+	for known := range permissionOps {
+		if strings.EqualFold(known, name) || strings.EqualFold("Op"+known, name) {
+			errs = append(errs, fmt.Errorf("%q: unknown permission %q, did you mean %q?", key, name, known))
+			return
+		}
+	}
+	errs = append(errs, fmt.Errorf("%q: unknown permission %q, expected an operation name such as GetSecret or CreateFolder", key, name))
+	return
+}
+
+// expandPermissions converts a set of permission names to operations, in a
+// stable order.
+func expandPermissions(names []interface{}) ([]request.Op, error) {
+	ops := make([]request.Op, 0, len(names))
+	for _, name := range names {
+		op, ok := permissionOps[name.(string)]
+		if !ok {
+			return nil, fmt.Errorf("unknown permission %q", name)
+		}
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i] < ops[j] })
+	return ops, nil
+}
+
+func flattenPermissions(ops []request.Op) []interface{} {
+	names := make([]interface{}, 0, len(ops))
+	for _, op := range ops {
+		names = append(names, permissionName(op))
+	}
+	return names
+}
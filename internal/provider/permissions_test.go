@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+)
+
+func TestPermissionNames(t *testing.T) {
+	for name, v := range request.Op_value {
+		op := request.Op(v)
+		got, err := expandPermissions([]interface{}{permissionName(op)})
+		if err != nil || len(got) != 1 || got[0] != op {
+			t.Errorf("%s does not round trip through %q: %v, %v", name, permissionName(op), got, err)
+		}
+	}
+	if got := permissionName(request.Op_OPDeletePin); got != "DeletePin" {
+		t.Errorf("permissionName(OPDeletePin) = %q, want DeletePin", got)
+	}
+	if got := permissionName(request.Op(9999)); got != "9999" {
+		t.Errorf("permissionName of an unknown op = %q, want its number", got)
+	}
+}
+
+func TestExpandPermissionsSorted(t *testing.T) {
+	got, err := expandPermissions([]interface{}{"ListSecrets", "AddUser", "GetSecret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []request.Op{request.Op_OpAddUser, request.Op_OpGetSecret, request.Op_OpListSecrets}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandPermissions = %v, want %v", got, want)
+	}
+}
+
+func TestValidatePermission(t *testing.T) {
+	tests := []struct {
+		name, wantErr string
+	}{
+		{"GetSecret", ""},
+		{"DeletePin", ""},
+		{"getsecret", `did you mean "GetSecret"`},
+		{"OpGetSecret", `did you mean "GetSecret"`},
+		{"ReadEverything", "such as GetSecret"},
+	}
+	for _, tt := range tests {
+		_, errs := validatePermission(tt.name, "permissions")
+		switch {
+		case tt.wantErr == "" && len(errs) != 0:
+			t.Errorf("%s: unexpected errors %v", tt.name, errs)
+		case tt.wantErr != "" && (len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr)):
+			t.Errorf("%s: errors %v, want one containing %q", tt.name, errs, tt.wantErr)
+		}
+	}
+}
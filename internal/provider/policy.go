@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/protobuf/proto"
+)
+
+// folderPolicy is the policy of a folder. Each attribute travels as a
+// KeyValMapping whose value is CBOR encoded; nil fields are not part of the
+// policy.
+type folderPolicy struct {
+	MaxSize             *uint64
+	MaxFileSize         *uint64
+	MaxFileVersions     *uint64
+	RemoveOlderVersions *bool
+	DefaultTTLForFiles  *uint64
+
+	// Unknown lists the attributes of a decoded policy that the provider
+	// does not know, in the order the server sent them.
+	Unknown []string
+}
+
+// policyFromResourceData returns the policy configured on a
+// ysafe_access_policy resource.
+func policyFromResourceData(d *schema.ResourceData) folderPolicy {
+	var p folderPolicy
+	uintAttr := func(attr string) *uint64 {
+		if v, ok := d.GetOk(attr); ok {
+			u := uint64(v.(int))
+			return &u
+		}
+		return nil
+	}
+	p.MaxSize = uintAttr("max_size")
+	p.MaxFileSize = uintAttr("max_file_size")
+	p.MaxFileVersions = uintAttr("max_file_versions")
+	p.DefaultTTLForFiles = uintAttr("default_ttl_for_files")
+	removeOlderVersions := d.Get("remove_older_versions").(bool)
+	p.RemoveOlderVersions = &removeOlderVersions
+	return p
+}
+
+// setResourceData stores the policy on a ysafe_access_policy resource.
+// Limits missing from the policy are zeroed only when state has a value for
+// them, so unset attributes stay null; remove_older_versions falls back to
+// the default of the server.
+func (p folderPolicy) setResourceData(d *schema.ResourceData) {
+	setOptionalUintPtr(d, "max_size", p.MaxSize)
+	setOptionalUintPtr(d, "max_file_size", p.MaxFileSize)
+	setOptionalUintPtr(d, "max_file_versions", p.MaxFileVersions)
+	setOptionalUintPtr(d, "default_ttl_for_files", p.DefaultTTLForFiles)
+	removeOlderVersions := true
+	if p.RemoveOlderVersions != nil {
+		removeOlderVersions = *p.RemoveOlderVersions
+	}
+	d.Set("remove_older_versions", removeOlderVersions)
+}
+
+// setDataSourceData stores the policy on a ysafe_folder data source. Only
+// the attributes the policy sets are stored, so the others stay null.
+func (p folderPolicy) setDataSourceData(d *schema.ResourceData) {
+	for _, f := range p.fields() {
+		switch ptr := f.ptr.(type) {
+		case **uint64:
+			if *ptr != nil {
+				d.Set(f.attr, int(**ptr))
+			}
+		case **bool:
+			if *ptr != nil {
+				d.Set(f.attr, **ptr)
+			}
+		}
+	}
+}
+
+// encode returns the policy in the form CreateFolder expects.
+func (p folderPolicy) encode() (*request.Policy, error) {
+	policy := &request.Policy{}
+	for _, f := range p.fields() {
+		value, err := f.encode()
+		if err != nil {
+			return nil, fmt.Errorf("policy attribute %s: %w", f.attr, err)
+		}
+		if value == nil {
+			continue
+		}
+		policy.AttrToValue = append(policy.AttrToValue, &request.KeyValMapping{
+			Attribute: f.attr,
+			Value:     value,
+		})
+	}
+	return policy, nil
+}
+
+// decodePolicy decodes the serialized request.Policy found in folder
+// metadata in a single pass over its attributes. Repeated attributes and
+// values that are not of the expected CBOR type are errors, since silently
+// dropping them would hide a policy the server enforces. Attributes added on
+// the server after this provider was built are listed in Unknown rather than
+// failing every read of the folder; see unknownDiags.
+func decodePolicy(data []byte) (folderPolicy, error) {
+	var p folderPolicy
+	var policy request.Policy
+	if err := proto.Unmarshal(data, &policy); err != nil {
+		return folderPolicy{}, fmt.Errorf("decoding policy: %w", err)
+	}
+	fields := map[string]policyField{}
+	for _, f := range p.fields() {
+		fields[f.attr] = f
+	}
+	seen := map[string]bool{}
+	for _, kv := range policy.AttrToValue {
+		if seen[kv.Attribute] {
+			return folderPolicy{}, fmt.Errorf("policy attribute %s appears more than once", kv.Attribute)
+		}
+		seen[kv.Attribute] = true
+		f, ok := fields[kv.Attribute]
+		if !ok {
+			p.Unknown = append(p.Unknown, kv.Attribute)
+			continue
+		}
+		if err := f.decode(kv.Value); err != nil {
+			return folderPolicy{}, fmt.Errorf("policy attribute %s: %w", kv.Attribute, err)
+		}
+	}
+	return p, nil
+}
+
+// unknownDiags warns about the attributes of the policy of the folder at
+// path that the provider does not know and therefore leaves out of state.
+func (p folderPolicy) unknownDiags(path string) diag.Diagnostics {
+	if len(p.Unknown) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Unknown policy attributes",
+		Detail: fmt.Sprintf("The policy of folder %s sets %s, which this version of the provider does not know. "+
+			"The server enforces them, but they are not part of the state.", path, strings.Join(p.Unknown, ", ")),
+	}}
+}
+
+// policyField ties a policy attribute to its field in folderPolicy.
+type policyField struct {
+	attr string
+	// ptr is a **uint64 or **bool pointing at the field.
+	ptr any
+}
+
+func (p *folderPolicy) fields() []policyField {
+	return []policyField{
+		{"max_size", &p.MaxSize},
+		{"max_file_size", &p.MaxFileSize},
+		{"max_file_versions", &p.MaxFileVersions},
+		{"remove_older_versions", &p.RemoveOlderVersions},
+		{"default_ttl_for_files", &p.DefaultTTLForFiles},
+	}
+}
+
+// encode returns the CBOR encoding of the field, or nil when it is unset.
+func (f policyField) encode() ([]byte, error) {
+	switch ptr := f.ptr.(type) {
+	case **uint64:
+		if *ptr == nil {
+			return nil, nil
+		}
+		return cbor.Marshal(**ptr)
+	case **bool:
+		if *ptr == nil {
+			return nil, nil
+		}
+		return cbor.Marshal(**ptr)
+	}
+	return nil, fmt.Errorf("unsupported field type %T", f.ptr)
+}
+
+func (f policyField) decode(data []byte) error {
+	switch ptr := f.ptr.(type) {
+	case **uint64:
+		var v uint64
+		if err := decodePolicyValue(data, &v, "an unsigned integer"); err != nil {
+			return err
+		}
+		// Terraform numbers in the schema are int64.
+		if v > math.MaxInt64 {
+			return fmt.Errorf("%d is larger than the largest supported value %d", v, int64(math.MaxInt64))
+		}
+		*ptr = &v
+	case **bool:
+		var v bool
+		if err := decodePolicyValue(data, &v, "a boolean"); err != nil {
+			return err
+		}
+		*ptr = &v
+	default:
+		return fmt.Errorf("unsupported field type %T", f.ptr)
+	}
+	return nil
+}
+
+// decodePolicyValue decodes exactly one CBOR data item of the wanted type.
+// The cbor package leaves v untouched for null and undefined, which would
+// turn a corrupt value into a zero limit, so those are rejected up front.
+func decodePolicyValue(data []byte, v any, want string) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty value, want %s", want)
+	}
+	if data[0] == cborNull || data[0] == cborUndefined {
+		return fmt.Errorf("null value, want %s", want)
+	}
+	if err := cbor.Unmarshal(data, v); err != nil {
+		var typeErr *cbor.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("got CBOR %s, want %s", typeErr.CBORType, want)
+		}
+		return fmt.Errorf("corrupt CBOR value %x: %w", data, err)
+	}
+	return nil
+}
+
+const (
+	cborNull      = 0xf6
+	cborUndefined = 0xf7
+)
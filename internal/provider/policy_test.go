@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodePolicy(t *testing.T) {
+	u := func(v uint64) *uint64 { return &v }
+	b := func(v bool) *bool { return &v }
+	kv := func(attr string, value ...byte) *request.KeyValMapping {
+		return &request.KeyValMapping{Attribute: attr, Value: value}
+	}
+
+	tests := []struct {
+		name    string
+		attrs   []*request.KeyValMapping
+		want    folderPolicy
+		wantErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "all attributes",
+			attrs: []*request.KeyValMapping{
+				kv("max_size", 0x1a, 0x00, 0x10, 0x00, 0x00),
+				kv("max_file_size", 0x19, 0x10, 0x00),
+				kv("max_file_versions", 0x02),
+				kv("remove_older_versions", 0xf4),
+				kv("default_ttl_for_files", 0x1a, 0x00, 0x01, 0x51, 0x80),
+			},
+			want: folderPolicy{
+				MaxSize:             u(1048576),
+				MaxFileSize:         u(4096),
+				MaxFileVersions:     u(2),
+				RemoveOlderVersions: b(false),
+				DefaultTTLForFiles:  u(86400),
+			},
+		},
+		{
+			name: "order does not matter",
+			attrs: []*request.KeyValMapping{
+				kv("default_ttl_for_files", 0x18, 0x3c),
+				kv("remove_older_versions", 0xf5),
+				kv("max_size", 0x00),
+			},
+			want: folderPolicy{
+				MaxSize:             u(0),
+				RemoveOlderVersions: b(true),
+				DefaultTTLForFiles:  u(60),
+			},
+		},
+		{
+			name:  "largest size",
+			attrs: []*request.KeyValMapping{kv("max_size", 0x1b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)},
+			want:  folderPolicy{MaxSize: u(1<<63 - 1)},
+		},
+		{
+			name:    "size out of range",
+			attrs:   []*request.KeyValMapping{kv("max_size", 0x1b, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)},
+			wantErr: "policy attribute max_size: 9223372036854775808 is larger than the largest supported value",
+		},
+		{
+			name:  "unknown attributes",
+			attrs: []*request.KeyValMapping{kv("min_size", 0x01), kv("max_size", 0x01), kv("max_files", 0x62, 'n', 'o')},
+			want:  folderPolicy{MaxSize: u(1), Unknown: []string{"min_size", "max_files"}},
+		},
+		{
+			name:    "repeated attribute",
+			attrs:   []*request.KeyValMapping{kv("max_size", 0x01), kv("max_size", 0x02)},
+			wantErr: "policy attribute max_size appears more than once",
+		},
+		{
+			name:    "repeated unknown attribute",
+			attrs:   []*request.KeyValMapping{kv("min_size", 0x01), kv("min_size", 0x02)},
+			wantErr: "policy attribute min_size appears more than once",
+		},
+		{
+			name:    "negative size",
+			attrs:   []*request.KeyValMapping{kv("max_size", 0x20)},
+			wantErr: "policy attribute max_size: got CBOR negative integer, want an unsigned integer",
+		},
+		{
+			name:    "string size",
+			attrs:   []*request.KeyValMapping{kv("max_file_size", 0x62, '1', '0')},
+			wantErr: "policy attribute max_file_size: got CBOR UTF-8 text string, want an unsigned integer",
+		},
+		{
+			name:    "integer flag",
+			attrs:   []*request.KeyValMapping{kv("remove_older_versions", 0x01)},
+			wantErr: "policy attribute remove_older_versions: got CBOR positive integer, want a boolean",
+		},
+		{
+			name:    "null",
+			attrs:   []*request.KeyValMapping{kv("max_file_versions", 0xf6)},
+			wantErr: "policy attribute max_file_versions: null value, want an unsigned integer",
+		},
+		{
+			name:    "empty value",
+			attrs:   []*request.KeyValMapping{kv("default_ttl_for_files")},
+			wantErr: "policy attribute default_ttl_for_files: empty value, want an unsigned integer",
+		},
+		{
+			name:    "truncated",
+			attrs:   []*request.KeyValMapping{kv("max_size", 0x1a, 0x00, 0x10)},
+			wantErr: "policy attribute max_size: corrupt CBOR value 1a0010",
+		},
+		{
+			name:    "trailing bytes",
+			attrs:   []*request.KeyValMapping{kv("max_file_versions", 0x02, 0x03)},
+			wantErr: "policy attribute max_file_versions: corrupt CBOR value 0203",
+		},
+		{
+			// The big endian encoding the resource used to build for updates.
+			name:    "raw big endian",
+			attrs:   []*request.KeyValMapping{kv("max_size", 0, 0, 0, 0, 0, 0, 0x04, 0x00)},
+			wantErr: "policy attribute max_size: corrupt CBOR value 0000000000000400",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := proto.Marshal(&request.Policy{AttrToValue: tt.attrs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := decodePolicy(data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("decodePolicy() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodePolicy() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodePolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePolicyNotProtobuf(t *testing.T) {
+	if _, err := decodePolicy([]byte{0xff, 0xff}); err == nil {
+		t.Fatal("decodePolicy() succeeded on garbage")
+	}
+}
+
+func TestEncodePolicyRoundTrip(t *testing.T) {
+	size, versions, flag := uint64(1<<40), uint64(3), true
+	want := folderPolicy{MaxSize: &size, MaxFileVersions: &versions, RemoveOlderVersions: &flag}
+
+	policy, err := want.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(policy.AttrToValue); n != 3 {
+		t.Errorf("encode() wrote %d attributes, want 3 for the fields that are set", n)
+	}
+	data, err := proto.Marshal(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodePolicy(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
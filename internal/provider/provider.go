@@ -1,9 +1,13 @@
 package provider
 
 import (
+	"fmt"
+	"os"
 	"terraform-provider-izysafe/internal/client"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"context"
 
@@ -25,23 +29,108 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 				Description: "The six digit password created for authenticating user.",
 			},
+			"endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("YSAFE_ENDPOINT", client.DefaultEndpoint),
+				Description: "WebSocket URL of the ysafe service. Can also be set with the `YSAFE_ENDPOINT` environment variable. Defaults to `" + client.DefaultEndpoint + "`.",
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("YSAFE_CA_CERT_PEM", nil),
+				ConflictsWith: []string{"ca_cert_file"},
+				Description:   "PEM encoded CA certificates trusted in addition to the built-in roots. Can also be set with the `YSAFE_CA_CERT_PEM` environment variable.",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("YSAFE_CA_CERT_FILE", nil),
+				ConflictsWith: []string{"ca_cert_pem"},
+				Description:   "Path to a PEM file with CA certificates trusted in addition to the built-in roots. Can also be set with the `YSAFE_CA_CERT_FILE` environment variable.",
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("YSAFE_TLS_SERVER_NAME", nil),
+				Description: "Server name used to verify the certificate of the endpoint, if it differs from the endpoint host. Can also be set with the `YSAFE_TLS_SERVER_NAME` environment variable.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("YSAFE_INSECURE_SKIP_VERIFY", false),
+				Description: "Disable verification of the server certificate. Only use this for testing. Can also be set with the `YSAFE_INSECURE_SKIP_VERIFY` environment variable.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of times a read request is retried after the connection to the server was lost. Default 3.",
+			},
+			"retry_max_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultRetryMaxBackoff / time.Second),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of seconds to wait between two retries. Default 30.",
+			},
+			"max_connections": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultPoolSize,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of connections opened to the server, which bounds how many requests run in parallel. Default 4.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"ysafe_access_token":  resourceAccessToken(),
-			"ysafe_access_policy": resourceAccessPolicy(),
+			"ysafe_access_token":      resourceAccessToken(),
+			"ysafe_access_policy":     resourceAccessPolicy(),
+			"ysafe_secret":            resourceSecret(),
+			"ysafe_generated_secret":  resourceGeneratedSecret(),
+			"ysafe_role":              resourceRole(),
+			"ysafe_team":              resourceTeam(),
+			"ysafe_user_assignment":   resourceUserAssignment(),
+			"ysafe_share":             resourceShare(),
+			"ysafe_organization_user": resourceOrganizationUser(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"ysafe_secret":  dataSourceSecret(),
+			"ysafe_secrets": dataSourceSecrets(),
+			"ysafe_users":   dataSourceUsers(),
+			"ysafe_roles":   dataSourceRoles(),
+			"ysafe_teams":   dataSourceTeams(),
+			"ysafe_folder":  dataSourceFolder(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	token := d.Get("token").(string)
-	endpoint := "wss://files.ysafe.io:5577"
-	pin := d.Get("pin").(string)
+	cfg := client.Config{
+		Endpoint:           d.Get("endpoint").(string),
+		Token:              d.Get("token").(string),
+		Pin:                d.Get("pin").(string),
+		TLSServerName:      d.Get("tls_server_name").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		MaxRetries:         d.Get("max_retries").(int),
+		RetryMaxBackoff:    time.Duration(d.Get("retry_max_backoff").(int)) * time.Second,
+		PoolSize:           d.Get("max_connections").(int),
+	}
+	if v, ok := d.GetOk("ca_cert_pem"); ok {
+		cfg.CACertPEM = []byte(v.(string))
+	}
+	if v, ok := d.GetOk("ca_cert_file"); ok {
+		pem, err := os.ReadFile(v.(string))
+		if err != nil {
+			return nil, diag.FromErr(fmt.Errorf("failed to read ca_cert_file: %w", err))
+		}
+		cfg.CACertPEM = pem
+	}
 
-	client := client.GetClient(token, endpoint, pin)
-	if client == nil {
-		return nil, diag.Errorf("Failed to create client. Please check the token and pin. Contact support if the issue persists.")
+	client, err := client.GetClient(ctx, cfg)
+	if err != nil {
+		return nil, diag.Errorf("Failed to create client: %v. Please check the token and pin. Contact support if the issue persists.", err)
 	}
 	return client, nil
 }
@@ -1,9 +1,15 @@
 package provider_test
 
 import (
+	"context"
 	"testing"
 
+	"terraform-provider-izysafe/internal/client"
+	"terraform-provider-izysafe/internal/proto/request"
 	"terraform-provider-izysafe/internal/provider"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestProvider(t *testing.T) {
@@ -18,3 +24,48 @@ func TestProvider(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestProviderAliases(t *testing.T) {
+	srv := ysafetest.NewServer(t)
+	orgs := map[string]string{
+		"one@example.com": srv.AddAccount("one@example.com", "111111"),
+		"two@example.com": srv.AddAccount("two@example.com", "222222"),
+	}
+	pins := map[string]string{
+		"one@example.com": "111111",
+		"two@example.com": "222222",
+	}
+
+	for email, token := range orgs {
+		p := provider.Provider()
+		diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+			"token":       token,
+			"pin":         pins[email],
+			"endpoint":    srv.URL,
+			"ca_cert_pem": srv.CACertPEM(),
+		}))
+		if diags.HasError() {
+			t.Fatalf("configure %s: %v", email, diags)
+		}
+		c := p.Meta().(*client.Client)
+		if c.Email != email {
+			t.Fatalf("alias for %s signed in as %s", email, c.Email)
+		}
+		_, err := c.Send(&request.Request{
+			Operation: &request.Request_Echo{Echo: &request.Echo{Data: []byte(email)}},
+		})
+		if err != nil {
+			t.Fatalf("send as %s: %v", email, err)
+		}
+	}
+
+	reqs := srv.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+	for _, r := range reqs {
+		if got := string(r.Request.GetEcho().GetData()); got != r.Email {
+			t.Errorf("request from the %s alias was served by the session of %s", got, r.Email)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Description:   "Manages a role: a named set of operations that users and teams holding it are allowed to run.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Unique name of the role.",
+			},
+			"permissions": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validatePermission,
+				},
+				Description: "Operations the role allows, named after the operations of the ysafe protocol " +
+					"without the `Op` prefix, such as `GetSecret`, `ListSecrets` or `CreateFolder`.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hex encoded uuid of the role.",
+			},
+		},
+	}
+}
+
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	name := d.Get("name").(string)
+	ops, err := expandPermissions(d.Get("permissions").(*schema.Set).List())
+	if err != nil {
+		return diag.Errorf("Invalid permissions: %s", err)
+	}
+	err = client.AddRole(ctx, &request.AddRole{
+		Name:        name,
+		Permissions: ops,
+	})
+	if err != nil {
+		return errorDiags("Add Role failed", err, cty.GetAttrPath("name"))
+	}
+	d.SetId(name)
+
+	return resourceRoleRead(ctx, d, m)
+}
+
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	role, err := client.GetRole(ctx, d.Id())
+	if isNotFound(err) {
+		// Deleted outside of Terraform; plan to create it again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read Role failed", err, cty.GetAttrPath("name"))
+	}
+	d.Set("name", role.Name)
+	d.Set("permissions", flattenPermissions(role.Permissions))
+	d.Set("uuid", hex.EncodeToString(role.Uuid))
+	return nil
+}
+
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	ops, err := expandPermissions(d.Get("permissions").(*schema.Set).List())
+	if err != nil {
+		return diag.Errorf("Invalid permissions: %s", err)
+	}
+	err = client.UpdateRole(ctx, &request.UpdateRole{
+		Name:        d.Id(),
+		Permissions: ops,
+	})
+	if err != nil {
+		return errorDiags("Update Role failed", err, cty.GetAttrPath("permissions"))
+	}
+	return resourceRoleRead(ctx, d, m)
+}
+
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	err := client.RemoveRole(ctx, d.Id())
+	if err != nil && !isNotFound(err) {
+		return errorDiags("Delete Role failed", err, cty.GetAttrPath("name"))
+	}
+	return nil
+}
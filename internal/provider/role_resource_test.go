@@ -0,0 +1,141 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRoleBasic(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := func(permissions string) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_role" "test" {
+				name        = "secret-readers"
+				permissions = %s
+			}
+		`, permissions)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["GetSecret", "ListSecrets"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_role.test", "id", "secret-readers"),
+					resource.TestCheckResourceAttr("ysafe_role.test", "permissions.#", "2"),
+					resource.TestCheckTypeSetElemAttr("ysafe_role.test", "permissions.*", "GetSecret"),
+					resource.TestCheckResourceAttrSet("ysafe_role.test", "uuid"),
+					testAccCheckRolePermissions(srv, "secret-readers", request.Op_OpGetSecret, request.Op_OpListSecrets),
+				),
+			},
+			{
+				// Permissions are updated in place.
+				Config: config(`["GetSecret", "ListSecrets", "DeletePin"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_role.test", "permissions.#", "3"),
+					testAccCheckRolePermissions(srv, "secret-readers", request.Op_OpGetSecret, request.Op_OpListSecrets, request.Op_OPDeletePin),
+				),
+			},
+			{
+				ResourceName:      "ysafe_role.test",
+				ImportState:       true,
+				ImportStateId:     "secret-readers",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccRoleDrift(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := providerConfig + `
+		resource "ysafe_role" "test" {
+			name        = "auditors"
+			permissions = ["GetAuditLog"]
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					c := testAccClient(t, srv)
+					err := c.UpdateRole(context.Background(), &request.UpdateRole{
+						Name:        "auditors",
+						Permissions: []request.Op{request.Op_OpGetAuditLog, request.Op_OpDeleteAuditLog},
+					})
+					if err != nil {
+						t.Fatalf("UpdateRole: %v", err)
+					}
+				},
+				Config: config,
+				Check:  testAccCheckRolePermissions(srv, "auditors", request.Op_OpGetAuditLog),
+			},
+			{
+				PreConfig: func() {
+					if err := testAccClient(t, srv).RemoveRole(context.Background(), "auditors"); err != nil {
+						t.Fatalf("RemoveRole: %v", err)
+					}
+				},
+				Config: config,
+				Check:  testAccCheckRolePermissions(srv, "auditors", request.Op_OpGetAuditLog),
+			},
+		},
+	})
+}
+
+func TestAccRoleInvalid(t *testing.T) {
+	_, providerConfig := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					resource "ysafe_role" "test" {
+						name        = "typo"
+						permissions = ["getsecret"]
+					}
+				`,
+				ExpectError: regexp.MustCompile(`unknown permission "getsecret", did you mean "GetSecret"`),
+			},
+		},
+	})
+}
+
+func testAccCheckRolePermissions(srv *ysafetest.Server, name string, want ...request.Op) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		role, ok := srv.Role(name)
+		if !ok {
+			return fmt.Errorf("role %s not found", name)
+		}
+		got := map[request.Op]bool{}
+		for _, op := range role.Permissions {
+			got[op] = true
+		}
+		if len(got) != len(want) {
+			return fmt.Errorf("role %s allows %v, want %v", name, role.Permissions, want)
+		}
+		for _, op := range want {
+			if !got[op] {
+				return fmt.Errorf("role %s allows %v, want %v", name, role.Permissions, want)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceRoles() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRolesRead,
+		Description: "Lists the roles of the organization, optionally with their permissions. Expanding the " +
+			"roles reads every role, one request each.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"expand": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read every role to fill in `roles`. Default false, which only lists the names.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the roles, sorted.",
+			},
+			"roles": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The roles, sorted by name. Only filled when `expand` is set.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the role.",
+						},
+						"permissions": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Operations the role allows, named like the permissions of `ysafe_role`, sorted.",
+						},
+						"uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hex encoded uuid of the role.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRolesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	list, err := client.ListRoles(ctx)
+	if err != nil {
+		return errorDiags("List Roles failed", err, nil)
+	}
+	sort.Strings(list)
+
+	names := []string{}
+	roles := []interface{}{}
+	expand := d.Get("expand").(bool)
+	for _, name := range list {
+		if !expand {
+			names = append(names, name)
+			continue
+		}
+		role, err := client.GetRole(ctx, name)
+		if isNotFound(err) {
+			// Removed since it was listed.
+			continue
+		}
+		if err != nil {
+			return errorDiags("Read Role failed", err, nil)
+		}
+		permissions := []string{}
+		for _, p := range flattenPermissions(role.Permissions) {
+			permissions = append(permissions, p.(string))
+		}
+		sort.Strings(permissions)
+		names = append(names, name)
+		roles = append(roles, map[string]interface{}{
+			"name":        name,
+			"permissions": permissions,
+			"uuid":        hex.EncodeToString(role.Uuid),
+		})
+	}
+
+	if expand {
+		d.SetId("roles expanded")
+	} else {
+		d.SetId("roles")
+	}
+	d.Set("names", names)
+	d.Set("roles", roles)
+	return nil
+}
@@ -0,0 +1,51 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRolesDataSource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	roles := map[string][]request.Op{
+		"writers": {request.Op_OpUpdateSecret, request.Op_OpAddSecret},
+		"readers": {request.Op_OpGetSecret},
+	}
+	for name, ops := range roles {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: ops}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					data "ysafe_roles" "names" {}
+
+					data "ysafe_roles" "expanded" {
+						expand = true
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ysafe_roles.names", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.names", "names.0", "readers"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.names", "roles.#", "0"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.expanded", "roles.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.expanded", "roles.1.name", "writers"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.expanded", "roles.1.permissions.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.expanded", "roles.1.permissions.0", "AddSecret"),
+					resource.TestCheckResourceAttr("data.ysafe_roles.expanded", "roles.1.permissions.1", "UpdateSecret"),
+					resource.TestCheckResourceAttrSet("data.ysafe_roles.expanded", "roles.1.uuid"),
+				),
+			},
+		},
+	})
+}
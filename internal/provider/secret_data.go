@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"google.golang.org/protobuf/proto"
+)
+
+// secretTypes are the nested blocks holding the data of a secret, one per
+// SecretData variant.
+var secretTypes = []string{
+	"password",
+	"card",
+	"note",
+	"identity",
+	"key_value",
+	"key",
+	"certificate",
+	"private_key",
+}
+
+const (
+	keyFormatRandom       = "random"
+	keyFormatAlphanumeric = "alphanumeric"
+)
+
+// secretField describes one attribute inside a secret type block.
+type secretField struct {
+	typ         schema.ValueType
+	required    bool
+	sensitive   bool
+	description string
+}
+
+var secretFields = map[string]map[string]secretField{
+	"password": {
+		"username":    {typ: schema.TypeString, description: "User name of the login."},
+		"password":    {typ: schema.TypeString, required: true, sensitive: true, description: "The password."},
+		"url":         {typ: schema.TypeString, description: "Address the login is used for."},
+		"totp_secret": {typ: schema.TypeString, sensitive: true, description: "Seed of the one-time password generator."},
+		"notes":       {typ: schema.TypeString, description: "Free form notes."},
+	},
+	"card": {
+		"number": {typ: schema.TypeString, required: true, sensitive: true, description: "Card number."},
+		"cvv":    {typ: schema.TypeString, sensitive: true, description: "Card verification value."},
+		"expiry": {typ: schema.TypeString, description: "Expiry date printed on the card."},
+		"notes":  {typ: schema.TypeString, description: "Free form notes."},
+	},
+	"note": {
+		"content": {typ: schema.TypeString, required: true, sensitive: true, description: "Text of the note."},
+	},
+	"identity": {
+		"number": {typ: schema.TypeString, required: true, sensitive: true, description: "Number of the identity document."},
+		"notes":  {typ: schema.TypeString, description: "Free form notes."},
+	},
+	"key_value": {
+		"values": {typ: schema.TypeMap, required: true, sensitive: true, description: "The key/value pairs."},
+	},
+	"key": {
+		"value":    {typ: schema.TypeString, required: true, sensitive: true, description: "The key. Base64 encoded when `format` is `random`, plain text when it is `alphanumeric`."},
+		"format":   {typ: schema.TypeString, description: "Either `random` for arbitrary bytes or `alphanumeric`. Default `random`."},
+		"min_size": {typ: schema.TypeInt, description: "Smallest size in bytes the key may have."},
+		"max_size": {typ: schema.TypeInt, description: "Largest size in bytes the key may have."},
+	},
+	"certificate": {
+		"pem": {typ: schema.TypeString, required: true, description: "PEM encoded certificate."},
+	},
+	"private_key": {
+		"pem": {typ: schema.TypeString, required: true, sensitive: true, description: "PEM encoded private key."},
+	},
+}
+
+var secretTypeDescriptions = map[string]string{
+	"password":    "A login: user name, password and where to use them.",
+	"card":        "A payment card.",
+	"note":        "A secure note.",
+	"identity":    "An identity document.",
+	"key_value":   "Arbitrary key/value pairs.",
+	"key":         "A symmetric key.",
+	"certificate": "An X.509 certificate.",
+	"private_key": "A private key.",
+}
+
+// secretDataSchema returns one block per secret type. In a resource exactly
+// one of them has to be configured; in a data source they are all computed,
+// sensitive, and only the one matching the type of the secret is filled in.
+func secretDataSchema(computed bool) map[string]*schema.Schema {
+	blocks := map[string]*schema.Schema{}
+	for _, typ := range secretTypes {
+		fields := map[string]*schema.Schema{}
+		for name, f := range secretFields[typ] {
+			s := &schema.Schema{
+				Type:        f.typ,
+				Sensitive:   f.sensitive,
+				Description: f.description,
+			}
+			switch {
+			case computed:
+				// Data sources feed other resources; keep every value
+				// out of plan output.
+				s.Computed = true
+				s.Sensitive = true
+			case f.required:
+				s.Required = true
+			default:
+				s.Optional = true
+			}
+			if f.typ == schema.TypeMap {
+				s.Elem = &schema.Schema{Type: schema.TypeString}
+			}
+			fields[name] = s
+		}
+		block := &schema.Schema{
+			Type:        schema.TypeList,
+			Description: secretTypeDescriptions[typ],
+			Elem:        &schema.Resource{Schema: fields},
+		}
+		if computed {
+			block.Computed = true
+		} else {
+			block.Optional = true
+			block.MaxItems = 1
+			block.ExactlyOneOf = secretTypes
+		}
+		blocks[typ] = block
+	}
+	if !computed {
+		key := blocks["key"].Elem.(*schema.Resource).Schema
+		key["format"].Default = keyFormatRandom
+		key["format"].ValidateFunc = validation.StringInSlice([]string{keyFormatRandom, keyFormatAlphanumeric}, false)
+		key["min_size"].ValidateFunc = validation.IntAtLeast(1)
+		key["max_size"].ValidateFunc = validation.IntAtLeast(1)
+	}
+	return blocks
+}
+
+// expandSecretData builds the SecretData of the configured secret type block.
+func expandSecretData(d *schema.ResourceData) (*request.SecretData, error) {
+	for _, typ := range secretTypes {
+		v, ok := d.GetOk(typ)
+		if !ok {
+			continue
+		}
+		list := v.([]interface{})
+		if len(list) == 0 || list[0] == nil {
+			return nil, fmt.Errorf("%s block is empty", typ)
+		}
+		return expandSecretType(typ, list[0].(map[string]interface{}))
+	}
+	return nil, fmt.Errorf("one of %v must be configured", secretTypes)
+}
+
+func expandSecretType(typ string, m map[string]interface{}) (*request.SecretData, error) {
+	str := func(k string) string {
+		s, _ := m[k].(string)
+		return s
+	}
+	switch typ {
+	case "password":
+		return &request.SecretData{
+			Type: request.SecretType_PASSWORD,
+			Data: &request.SecretData_Password{Password: &request.Password{
+				Username:   str("username"),
+				Password:   str("password"),
+				Url:        str("url"),
+				TotpSecret: str("totp_secret"),
+				Notes:      str("notes"),
+			}},
+		}, nil
+	case "card":
+		return &request.SecretData{
+			Type: request.SecretType_CARD,
+			Data: &request.SecretData_Card{Card: &request.Card{
+				Number: str("number"),
+				Cvv:    str("cvv"),
+				Expiry: str("expiry"),
+				Notes:  str("notes"),
+			}},
+		}, nil
+	case "note":
+		return &request.SecretData{
+			Type: request.SecretType_NOTE,
+			Data: &request.SecretData_Note{Note: &request.Note{
+				Content: str("content"),
+			}},
+		}, nil
+	case "identity":
+		return &request.SecretData{
+			Type: request.SecretType_IDENTITY,
+			Data: &request.SecretData_Identity{Identity: &request.Identity{
+				Number: str("number"),
+				Notes:  str("notes"),
+			}},
+		}, nil
+	case "key_value":
+		kv := &request.KeyValue{}
+		values, _ := m["values"].(map[string]interface{})
+		for _, k := range sortedKeys(values) {
+			kv.Key = append(kv.Key, k)
+			kv.Value = append(kv.Value, values[k].(string))
+		}
+		return &request.SecretData{
+			Type: request.SecretType_KEYVALUES,
+			Data: &request.SecretData_KeyValue{KeyValue: kv},
+		}, nil
+	case "key":
+		key := &request.Key{}
+		if str("format") == keyFormatAlphanumeric {
+			key.Format = &request.Key_Alphanumeric{Alphanumeric: []byte(str("value"))}
+		} else {
+			b, err := base64.StdEncoding.DecodeString(str("value"))
+			if err != nil {
+				return nil, fmt.Errorf("key.value must be base64 encoded for format %q: %w", keyFormatRandom, err)
+			}
+			key.Format = &request.Key_Random{Random: b}
+		}
+		if n, _ := m["min_size"].(int); n > 0 {
+			size := int32(n)
+			key.MinSize = &size
+		}
+		if n, _ := m["max_size"].(int); n > 0 {
+			size := int32(n)
+			key.MaxSize = &size
+		}
+		return &request.SecretData{
+			Type: request.SecretType_KEYS,
+			Data: &request.SecretData_Key{Key: key},
+		}, nil
+	case "certificate":
+		return &request.SecretData{
+			Type: request.SecretType_CERTIFICATE,
+			Data: &request.SecretData_Certificate{Certificate: &request.Certificate{
+				Value: []byte(str("pem")),
+			}},
+		}, nil
+	case "private_key":
+		return &request.SecretData{
+			Type: request.SecretType_PRIVATEKEY,
+			Data: &request.SecretData_Privatekey{Privatekey: &request.PrivateKey{
+				Value: []byte(str("pem")),
+			}},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown secret type %q", typ)
+}
+
+// flattenSecretData returns the name of the block matching the type of data
+// and its contents.
+func flattenSecretData(data *response.SecretData) (string, map[string]interface{}, error) {
+	switch v := data.GetData().(type) {
+	case *response.SecretData_Password:
+		return "password", map[string]interface{}{
+			"username":    v.Password.GetUsername(),
+			"password":    v.Password.GetPassword(),
+			"url":         v.Password.GetUrl(),
+			"totp_secret": v.Password.GetTotpSecret(),
+			"notes":       v.Password.GetNotes(),
+		}, nil
+	case *response.SecretData_Card:
+		return "card", map[string]interface{}{
+			"number": v.Card.GetNumber(),
+			"cvv":    v.Card.GetCvv(),
+			"expiry": v.Card.GetExpiry(),
+			"notes":  v.Card.GetNotes(),
+		}, nil
+	case *response.SecretData_Note:
+		return "note", map[string]interface{}{
+			"content": v.Note.GetContent(),
+		}, nil
+	case *response.SecretData_Identity:
+		return "identity", map[string]interface{}{
+			"number": v.Identity.GetNumber(),
+			"notes":  v.Identity.GetNotes(),
+		}, nil
+	case *response.SecretData_KeyValue:
+		keys, values := v.KeyValue.GetKey(), v.KeyValue.GetValue()
+		if len(keys) != len(values) {
+			return "", nil, fmt.Errorf("key/value secret has %d keys but %d values", len(keys), len(values))
+		}
+		m := map[string]interface{}{}
+		for i, k := range keys {
+			m[k] = values[i]
+		}
+		return "key_value", map[string]interface{}{
+			"values": m,
+		}, nil
+	case *response.SecretData_Key:
+		key := map[string]interface{}{
+			"min_size": int(v.Key.GetMinSize()),
+			"max_size": int(v.Key.GetMaxSize()),
+		}
+		switch f := v.Key.GetFormat().(type) {
+		case *response.Key_Alphanumeric:
+			key["format"] = keyFormatAlphanumeric
+			key["value"] = string(f.Alphanumeric)
+		case *response.Key_Random:
+			key["format"] = keyFormatRandom
+			key["value"] = base64.StdEncoding.EncodeToString(f.Random)
+		default:
+			return "", nil, fmt.Errorf("key secret has no value")
+		}
+		return "key", key, nil
+	case *response.SecretData_Certificate:
+		return "certificate", map[string]interface{}{
+			"pem": string(v.Certificate.GetValue()),
+		}, nil
+	case *response.SecretData_Privatekey:
+		return "private_key", map[string]interface{}{
+			"pem": string(v.Privatekey.GetValue()),
+		}, nil
+	}
+	return "", nil, fmt.Errorf("secret of type %s has no data", data.GetType())
+}
+
+// setSecretData fills in the block matching the type of data, clears all
+// the others and returns the name of the block it filled in.
+func setSecretData(d *schema.ResourceData, data *response.SecretData) (string, error) {
+	typ, values, err := flattenSecretData(data)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range secretTypes {
+		var v []interface{}
+		if t == typ {
+			v = []interface{}{values}
+		}
+		if err := d.Set(t, v); err != nil {
+			return "", err
+		}
+	}
+	return typ, nil
+}
+
+// sameSecretData reports whether the data about to be sent equals the data
+// of a stored version. Both flavours of SecretData share their wire format.
+func sameSecretData(a *request.SecretData, b *response.SecretData) bool {
+	opts := proto.MarshalOptions{Deterministic: true}
+	ab, err := opts.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := opts.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+func secretVersionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Every version of the secret, oldest first.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "Id of the version, counting up from 1.",
+				},
+				"creation_time": {
+					Type:        schema.TypeInt,
+					Computed:    true,
+					Description: "Unix time (in s) the version was stored.",
+				},
+			},
+		},
+	}
+}
+
+// flattenSecretVersions lists the versions of secret ordered by id.
+func flattenSecretVersions(secret *response.Secret) []interface{} {
+	versions := append([]*response.SecretVersion(nil), secret.Versions...)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Id < versions[j].Id })
+	out := make([]interface{}, len(versions))
+	for i, v := range versions {
+		out[i] = map[string]interface{}{
+			"id":            int(v.Id),
+			"creation_time": int(v.CreationTime),
+		}
+	}
+	return out
+}
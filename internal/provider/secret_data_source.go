@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceSecret() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"path": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validateSecretPath,
+			Description:  "Path of the secret, starting with `/`.",
+		},
+		"version": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Id of the version to read. Defaults to the latest version.",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the block holding the data of the secret, such as `password` or `certificate`.",
+		},
+		"expiry": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Unix time (in s) after which the secret is reported as expired, 0 if it never expires.",
+		},
+		"rotate_interval": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Time (in s) after which the secret should be rotated, 0 if it is not rotated.",
+		},
+		"last_rotated_time": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Unix time (in s) the latest version of the secret was stored.",
+		},
+		"is_expired": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the expiry of the secret has passed.",
+		},
+		"versions": secretVersionsSchema(),
+	}
+	for name, block := range secretDataSchema(true) {
+		s[name] = block
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceSecretRead,
+		Description: "Reads a secret. Only the block matching the type of the secret is filled in.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: s,
+	}
+}
+
+func dataSourceSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	path := d.Get("path").(string)
+	secret, err := client.GetSecret(ctx, path)
+	if err != nil {
+		return errorDiags("Read Secret failed", err, cty.GetAttrPath("path"))
+	}
+
+	var version *response.SecretVersion
+	if v, ok := d.GetOk("version"); ok {
+		version = findSecretVersion(secret, uint64(v.(int)))
+		if version == nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Secret version not found",
+				Detail:        fmt.Sprintf("Secret %s has no version %d. Available versions: %s.", path, v.(int), secretVersionIds(secret)),
+				AttributePath: cty.GetAttrPath("version"),
+			}}
+		}
+	} else {
+		version = latestSecretVersion(secret)
+		if version == nil {
+			return diag.Errorf("Secret %s has no versions. Read Secret failed!!!", path)
+		}
+	}
+
+	typ, err := setSecretData(d, version.SecretData)
+	if err != nil {
+		return diag.Errorf("Data Corrupted. Read Secret failed: %s", err)
+	}
+	d.SetId(path)
+	d.Set("version", int(version.Id))
+	d.Set("type", typ)
+	d.Set("expiry", int(secret.Expiry))
+	d.Set("rotate_interval", int(secret.RotateInterval))
+	d.Set("last_rotated_time", int(secret.LastRotatedTime))
+	d.Set("is_expired", secret.IsExpired)
+	d.Set("versions", flattenSecretVersions(secret))
+	return nil
+}
+
+// findSecretVersion returns the version of secret with the given id.
+func findSecretVersion(secret *response.Secret, id uint64) *response.SecretVersion {
+	for _, v := range secret.Versions {
+		if v.Id == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// secretVersionIds lists the version ids of secret in ascending order, for
+// error messages.
+func secretVersionIds(secret *response.Secret) string {
+	var ids []string
+	for _, v := range flattenSecretVersions(secret) {
+		ids = append(ids, strconv.Itoa(v.(map[string]interface{})["id"].(int)))
+	}
+	return strings.Join(ids, ", ")
+}
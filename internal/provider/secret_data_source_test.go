@@ -0,0 +1,112 @@
+package provider_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSecretDataSource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	err := c.AddSecret(ctx, &request.AddSecret{
+		Path: "/prod/db",
+		SecretData: &request.SecretData{
+			Type: request.SecretType_PASSWORD,
+			Data: &request.SecretData_Password{Password: &request.Password{
+				Username: "admin",
+				Password: "first",
+				Url:      "postgres://db.example.com",
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddSecret: %v", err)
+	}
+	testAccUpdateSecret(t, srv, "/prod/db", "now a note")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					data "ysafe_secret" "latest" {
+						path = "/prod/db"
+					}
+
+					data "ysafe_secret" "first" {
+						path    = "/prod/db"
+						version = 1
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "version", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "type", "note"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "note.0.content", "now a note"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "password.#", "0"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "versions.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "versions.0.id", "1"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.latest", "versions.1.id", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.first", "version", "1"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.first", "type", "password"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.first", "password.0.username", "admin"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.first", "password.0.password", "first"),
+				),
+			},
+			{
+				Config: providerConfig + `
+					data "ysafe_secret" "missing" {
+						path    = "/prod/db"
+						version = 7
+					}
+				`,
+				ExpectError: regexp.MustCompile(`has no version 7. Available versions: 1, 2`),
+			},
+			{
+				Config: providerConfig + `
+					data "ysafe_secret" "missing" {
+						path = "/prod/nothing"
+					}
+				`,
+				ExpectError: regexp.MustCompile(`OBJECT_NOT_FOUND`),
+			},
+		},
+	})
+}
+
+func TestAccSecretDataSourceFromResource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					resource "ysafe_secret" "kv" {
+						path = "/app/config"
+						key_value {
+							values = {
+								region = "eu-west-1"
+								bucket = "assets"
+							}
+						}
+					}
+
+					data "ysafe_secret" "kv" {
+						path = ysafe_secret.kv.path
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ysafe_secret.kv", "key_value.0.values.region", "eu-west-1"),
+					resource.TestCheckResourceAttr("data.ysafe_secret.kv", "key_value.0.values.bucket", "assets"),
+				),
+			},
+		},
+	})
+}
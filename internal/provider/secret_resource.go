@@ -0,0 +1,361 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	deletionModeSoft    = "soft"
+	deletionModeDestroy = "destroy"
+)
+
+func resourceSecret() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"path": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validateSecretPath,
+			Description:  "Path of the secret, starting with `/`.",
+		},
+		"expiry": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Unix time (in s) after which the secret is reported as expired.",
+		},
+		"rotate_interval": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description: "Time (in s) after which the secret should be rotated. The provider does not rotate this " +
+				"secret: once the interval has elapsed since `last_rotated_time`, plans only warn that it is overdue " +
+				"until its data is changed. Use `ysafe_generated_secret` to have new values generated on a " +
+				"schedule. Changing the interval replaces the secret, which requires `deletion_mode` to be " +
+				"`destroy`.",
+		},
+		"last_rotated_time": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Unix time (in s) the latest version of the secret was stored.",
+		},
+		"deletion_mode": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      deletionModeSoft,
+			ValidateFunc: validation.StringInSlice([]string{deletionModeSoft, deletionModeDestroy}, false),
+			Description: "What destroying the resource does to the secret. `soft` deletes it so that it can be " +
+				"recovered; `destroy` removes it and all of its versions for good. Default `soft`.",
+		},
+		"restore_deleted": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+			Description: "When a soft deleted secret already uses `path`, restore it with all of its versions " +
+				"instead of failing, then store the configured data as a new version if it differs. Default true.",
+		},
+		"is_expired": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the expiry of the secret has passed.",
+		},
+		"version": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Id of the latest version of the secret.",
+		},
+		"versions": secretVersionsSchema(),
+	}
+	for name, block := range secretDataSchema(false) {
+		s[name] = block
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceSecretCreate,
+		ReadContext:   resourceSecretRead,
+		UpdateContext: resourceSecretUpdate,
+		DeleteContext: resourceSecretDelete,
+		CustomizeDiff: resourceSecretCustomizeDiff,
+		Description: "Manages a secret. Exactly one block describing the data of the secret has to be set; " +
+			"changing the data stores a new version of the secret. The provider never rotates the data of this " +
+			"resource on its own; `ysafe_generated_secret` does.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSecretImportState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: s,
+	}
+}
+
+func validateSecretPath(val interface{}, key string) (warns []string, errs []error) {
+	path, ok := val.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %q to be a string", key))
+		return
+	}
+	if !strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		errs = append(errs, fmt.Errorf("%q must start with / and must not end with /, got %q", key, path))
+	}
+	return
+}
+
+func resourceSecretImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("path", d.Id())
+	d.Set("deletion_mode", deletionModeSoft)
+	d.Set("restore_deleted", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceSecretCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	path := d.Get("path").(string)
+	data, err := expandSecretData(d)
+	if err != nil {
+		return diag.Errorf("Invalid secret data: %s", err)
+	}
+	req := &request.AddSecret{
+		Path:       path,
+		SecretData: data,
+	}
+	if v, ok := d.GetOk("expiry"); ok {
+		expiry := uint64(v.(int))
+		req.Expiry = &expiry
+	}
+	if v, ok := d.GetOk("rotate_interval"); ok {
+		interval := uint64(v.(int))
+		req.RotateInterval = &interval
+	}
+	err = client.AddSecret(ctx, req)
+	if isExists(err) && d.Get("restore_deleted").(bool) {
+		return resourceSecretRestore(ctx, d, m, req, err, resourceSecretRead)
+	}
+	if err != nil {
+		return errorDiags("Add Secret failed", err, cty.GetAttrPath("path"))
+	}
+	d.SetId(path)
+
+	return resourceSecretRead(ctx, d, m)
+}
+
+// resourceSecretRestore undeletes the secret that made AddSecret fail with
+// addErr, brings it in line with the configuration and reads it back with
+// read.
+func resourceSecretRestore(ctx context.Context, d *schema.ResourceData, m interface{}, add *request.AddSecret, addErr error, read schema.ReadContextFunc) diag.Diagnostics {
+	client := m.(*client.Client)
+	err := client.UndeleteSecret(ctx, add.Path)
+	if isNotFound(err) {
+		// The secret is live, not deleted, so there is nothing to restore.
+		return errorDiags("Add Secret failed", addErr, cty.GetAttrPath("path"))
+	}
+	if err != nil {
+		return errorDiags("Restore Secret failed", err, cty.GetAttrPath("path"))
+	}
+
+	secret, err := client.GetSecret(ctx, add.Path)
+	if err != nil {
+		return errorDiags("Restore Secret failed", err, cty.GetAttrPath("path"))
+	}
+	if add.GetRotateInterval() != secret.RotateInterval {
+		// The server cannot change the interval, so restoring the secret
+		// would never match the configuration. Leave it deleted.
+		if err := client.DeleteSecret(ctx, add.Path); err != nil {
+			return errorDiags("Restore Secret failed", err, cty.GetAttrPath("path"))
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Restore Secret failed",
+			Detail: fmt.Sprintf("The soft deleted secret %s has a rotate_interval of %d s and the server cannot "+
+				"change it, so it was left deleted. Set rotate_interval to %d to restore it. To use a different "+
+				"interval, restore it, set deletion_mode = \"destroy\" and apply, then change rotate_interval.",
+				add.Path, secret.RotateInterval, secret.RotateInterval),
+			AttributePath: cty.GetAttrPath("rotate_interval"),
+		}}
+	}
+	d.SetId(add.Path)
+
+	update := &request.UpdateSecret{
+		Path:   add.Path,
+		Expiry: add.Expiry,
+	}
+	if update.Expiry == nil && secret.Expiry != 0 {
+		update.Expiry = new(uint64)
+	}
+	if latest := latestSecretVersion(secret); latest == nil || !sameSecretData(add.SecretData, latest.SecretData) {
+		update.SecretData = add.SecretData
+	}
+	if update.SecretData != nil || (update.Expiry != nil && *update.Expiry != secret.Expiry) {
+		if err := client.UpdateSecret(ctx, update); err != nil {
+			return errorDiags("Restore Secret failed", err, cty.GetAttrPath("path"))
+		}
+	}
+
+	return read(ctx, d, m)
+}
+
+func resourceSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	secret, err := client.GetSecret(ctx, d.Id())
+	if isNotFound(err) {
+		// Deleted outside of Terraform; plan to create it again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read Secret failed", err, cty.GetAttrPath("path"))
+	}
+	version := latestSecretVersion(secret)
+	if version == nil {
+		return diag.Errorf("Secret %s has no versions. Read Secret failed!!!", d.Id())
+	}
+	if _, err := setSecretData(d, version.SecretData); err != nil {
+		return diag.Errorf("Data Corrupted. Read Secret failed: %s", err)
+	}
+	d.Set("path", secret.Path)
+	setOptionalUint(d, "expiry", secret.Expiry)
+	setOptionalUint(d, "rotate_interval", secret.RotateInterval)
+	d.Set("last_rotated_time", int(secret.LastRotatedTime))
+	d.Set("is_expired", secret.IsExpired)
+	d.Set("version", int(version.Id))
+	d.Set("versions", flattenSecretVersions(secret))
+	if rotationDue(int(secret.RotateInterval), int(secret.LastRotatedTime), time.Now()) {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Secret is overdue for rotation",
+			Detail: fmt.Sprintf("The data of secret %s has not changed for longer than its rotate_interval of %d s. "+
+				"Change its data to store a new version, or manage it with ysafe_generated_secret to have a new "+
+				"value generated on a schedule.", d.Id(), secret.RotateInterval),
+			AttributePath: cty.GetAttrPath("rotate_interval"),
+		}}
+	}
+	return nil
+}
+
+func resourceSecretUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	req := &request.UpdateSecret{
+		Path: d.Id(),
+	}
+	if d.HasChanges(secretTypes...) {
+		data, err := expandSecretData(d)
+		if err != nil {
+			return diag.Errorf("Invalid secret data: %s", err)
+		}
+		req.SecretData = data
+	}
+	if d.HasChange("expiry") {
+		// Zero clears the expiry.
+		expiry := uint64(d.Get("expiry").(int))
+		req.Expiry = &expiry
+	}
+	if req.SecretData != nil || req.Expiry != nil {
+		if err := client.UpdateSecret(ctx, req); err != nil {
+			return errorDiags("Update Secret failed", err, cty.GetAttrPath("path"))
+		}
+	}
+	return resourceSecretRead(ctx, d, m)
+}
+
+func resourceSecretDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	var err error
+	if d.Get("deletion_mode").(string) == deletionModeDestroy {
+		err = client.DestroySecret(ctx, d.Id())
+	} else {
+		err = client.DeleteSecret(ctx, d.Id())
+	}
+	if err != nil && !isNotFound(err) {
+		return errorDiags("Delete Secret failed", err, cty.GetAttrPath("path"))
+	}
+	return nil
+}
+
+// resourceSecretCustomizeDiff rejects interval changes that cannot be applied
+// and marks what an update will recompute.
+func resourceSecretCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if err := checkRotateIntervalChange(d); err != nil {
+		return err
+	}
+	if d.HasChanges(secretTypes...) {
+		// New data is stored as a new version.
+		d.SetNewComputed("version")
+		d.SetNewComputed("versions")
+		d.SetNewComputed("last_rotated_time")
+	}
+	if d.HasChange("expiry") {
+		d.SetNewComputed("is_expired")
+	}
+	return nil
+}
+
+// checkRotateIntervalChange rejects a change of rotate_interval when the
+// secret would only be soft deleted by the replacement. The server keeps
+// the interval of a soft deleted secret and refuses to add one at its path,
+// so the replacement could not succeed. The replacement is destroyed with
+// the prior deletion_mode, so that is the value checked.
+func checkRotateIntervalChange(d *schema.ResourceDiff) error {
+	if !d.HasChange("rotate_interval") {
+		return nil
+	}
+	mode, _ := d.GetChange("deletion_mode")
+	if mode.(string) == deletionModeDestroy {
+		return nil
+	}
+	old, new := d.GetChange("rotate_interval")
+	return fmt.Errorf("rotate_interval of secret %q cannot be changed from %v to %v while deletion_mode is %q: "+
+		"the server keeps the interval of a soft deleted secret, so it could not be created again. Apply "+
+		"deletion_mode = %q first, which removes the secret and all of its versions when it is replaced, or "+
+		"revert the change", d.Id(), old, new, mode, deletionModeDestroy)
+}
+
+// rotationDue reports whether interval seconds have passed since
+// lastRotated, both Unix times in s. Zero for either means the secret is
+// not rotated.
+func rotationDue(interval, lastRotated int, now time.Time) bool {
+	return interval > 0 && lastRotated > 0 && now.Unix() >= int64(lastRotated)+int64(interval)
+}
+
+// latestSecretVersion returns the version with the highest id, or nil when
+// the secret has none.
+func latestSecretVersion(secret *response.Secret) *response.SecretVersion {
+	var latest *response.SecretVersion
+	for _, v := range secret.Versions {
+		if latest == nil || v.Id > latest.Id {
+			latest = v
+		}
+	}
+	return latest
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceSecrets() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSecretsRead,
+		Description: "Lists the paths and expiries of secrets, without their data. The server returns every " +
+			"secret in a single reply, so there is nothing to page through; the filters are applied by the provider.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "/",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/`), "must start with /"),
+				Description:  "Only list secrets whose path starts with this prefix. Default `/`.",
+			},
+			"regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only list secrets whose path matches this regular expression.",
+			},
+			"expiring_within_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description: "Only list secrets with an expiry less than this many days away, " +
+					"including the ones that have already expired.",
+			},
+			"paths": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Paths of the listed secrets, sorted.",
+			},
+			"secrets": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The listed secrets, sorted by path.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Path of the secret.",
+						},
+						"expiry": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Unix time (in s) after which the secret is reported as expired, 0 if it never expires.",
+						},
+						"is_expired": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the expiry of the secret has passed.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSecretsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	prefix := d.Get("prefix").(string)
+	var re *regexp.Regexp
+	if v, ok := d.GetOk("regex"); ok {
+		var err error
+		if re, err = regexp.Compile(v.(string)); err != nil {
+			return errorDiags("Invalid regex", err, cty.GetAttrPath("regex"))
+		}
+	}
+	now := uint64(time.Now().Unix())
+	var before uint64
+	days, filterExpiry := d.GetOk("expiring_within_days")
+	if filterExpiry {
+		before = now + uint64(days.(int))*24*60*60
+	}
+
+	list, err := client.QuickListSecrets(ctx)
+	if err != nil {
+		return errorDiags("List Secrets failed", err, nil)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+
+	paths := []string{}
+	secrets := []interface{}{}
+	for _, s := range list {
+		if !strings.HasPrefix(s.Path, prefix) {
+			continue
+		}
+		if re != nil && !re.MatchString(s.Path) {
+			continue
+		}
+		if filterExpiry && (s.Expiry == 0 || s.Expiry >= before) {
+			continue
+		}
+		paths = append(paths, s.Path)
+		secrets = append(secrets, map[string]interface{}{
+			"path":       s.Path,
+			"expiry":     int(s.Expiry),
+			"is_expired": s.Expiry != 0 && s.Expiry < now,
+		})
+	}
+
+	id := prefix
+	if re != nil {
+		id += " " + re.String()
+	}
+	if filterExpiry {
+		id += fmt.Sprintf(" %dd", days.(int))
+	}
+	d.SetId(id)
+	d.Set("paths", paths)
+	d.Set("secrets", secrets)
+	return nil
+}
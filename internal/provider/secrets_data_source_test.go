@@ -0,0 +1,73 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSecretsDataSource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	now := uint64(time.Now().Unix())
+	expiries := map[string]uint64{
+		"/prod/db/admin":    now + 3*24*60*60,
+		"/prod/db/readonly": now + 90*24*60*60,
+		"/prod/api/token":   now - 60,
+		"/dev/db/admin":     0,
+	}
+	for path, expiry := range expiries {
+		req := &request.AddSecret{Path: path, SecretData: &request.SecretData{
+			Type: request.SecretType_NOTE,
+			Data: &request.SecretData_Note{Note: &request.Note{Content: path}},
+		}}
+		if expiry != 0 {
+			req.Expiry = &expiry
+		}
+		if err := c.AddSecret(ctx, req); err != nil {
+			t.Fatalf("AddSecret: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					data "ysafe_secrets" "all" {}
+
+					data "ysafe_secrets" "prod" {
+						prefix = "/prod/"
+					}
+
+					data "ysafe_secrets" "admins" {
+						regex = "/admin$"
+					}
+
+					data "ysafe_secrets" "expiring" {
+						prefix               = "/prod/"
+						expiring_within_days = 30
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ysafe_secrets.all", "paths.#", "4"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.all", "paths.0", "/dev/db/admin"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.all", "secrets.0.expiry", "0"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.prod", "paths.#", "3"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.admins", "paths.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.admins", "paths.1", "/prod/db/admin"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.expiring", "secrets.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.expiring", "secrets.0.path", "/prod/api/token"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.expiring", "secrets.0.is_expired", "true"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.expiring", "secrets.1.path", "/prod/db/admin"),
+					resource.TestCheckResourceAttr("data.ysafe_secrets.expiring", "secrets.1.is_expired", "false"),
+				),
+			},
+		},
+	})
+}
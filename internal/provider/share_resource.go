@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceShare() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceShareCreate,
+		ReadContext:   resourceShareRead,
+		UpdateContext: resourceShareUpdate,
+		DeleteContext: resourceShareDelete,
+		Description: "Shares a folder with users and teams. The rules form the access control list of the folder " +
+			"and replace any rules set outside of Terraform.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"object_path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/.*[^/]$`), "must start with / and must not end with /"),
+				Description:  "Full path of the shared folder, such as `/docs`.",
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Rules of the access control list, in order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"action": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "allow",
+							ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+							Description:  "Whether the rule allows or denies the operations: `allow` or `deny`. Default `allow`.",
+						},
+						"subject": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "Email of the user or name of the team the rule applies to.",
+						},
+						"subject_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "user",
+							ValidateFunc: validation.StringInSlice([]string{"user", "team"}, false),
+							Description:  "What `subject` names: `user` or `team`. Default `user`.",
+						},
+						"operations": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validatePermission,
+							},
+							Description: "Operations the rule allows or denies, named like the permissions of `ysafe_role`, " +
+								"such as `GetSecret` or `CreateFolder`.",
+						},
+					},
+				},
+			},
+			"owner_email": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Email of the owner of the folder.",
+			},
+		},
+	}
+}
+
+func resourceShareCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	path := d.Get("object_path").(string)
+	acl, err := expandACL(path, d.Get("rule").([]interface{}))
+	if err != nil {
+		return diag.Errorf("Invalid rule: %s", err)
+	}
+	if err := client.Share(ctx, acl); err != nil {
+		return errorDiags("Share failed", err, cty.GetAttrPath("object_path"))
+	}
+	d.SetId(path)
+
+	return resourceShareRead(ctx, d, m)
+}
+
+func resourceShareRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	acl, err := client.ListShares(ctx, d.Id())
+	if isNotFound(err) {
+		// Unshared outside of Terraform; plan to share it again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read Share failed", err, cty.GetAttrPath("object_path"))
+	}
+	d.Set("object_path", d.Id())
+	d.Set("rule", flattenACL(acl))
+	d.Set("owner_email", acl.OwnerEmail)
+	return nil
+}
+
+func resourceShareUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	acl, err := expandACL(d.Id(), d.Get("rule").([]interface{}))
+	if err != nil {
+		return diag.Errorf("Invalid rule: %s", err)
+	}
+	if err := client.UpdateShare(ctx, acl); err != nil {
+		return errorDiags("Update Share failed", err, cty.GetAttrPath("rule"))
+	}
+	return resourceShareRead(ctx, d, m)
+}
+
+func resourceShareDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	err := client.Unshare(ctx, d.Id())
+	if err != nil && !isNotFound(err) {
+		return errorDiags("Unshare failed", err, cty.GetAttrPath("object_path"))
+	}
+	return nil
+}
+
+func expandACL(path string, rules []interface{}) (*request.ACL, error) {
+	acl := &request.ACL{
+		ObjectPath: path,
+		TypeOfPath: request.TypeOfPath_TFolder,
+		Actions:    make([]*request.ActionEntry, 0, len(rules)),
+	}
+	for _, r := range rules {
+		rule := r.(map[string]interface{})
+		action, ok := request.Action_value[strings.ToUpper(rule["action"].(string))]
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q", rule["action"])
+		}
+		subjectType, ok := request.SubjectType_value[strings.ToUpper(rule["subject_type"].(string))]
+		if !ok {
+			return nil, fmt.Errorf("unknown subject type %q", rule["subject_type"])
+		}
+		ops, err := expandPermissions(rule["operations"].(*schema.Set).List())
+		if err != nil {
+			return nil, err
+		}
+		opIds := make([]int32, 0, len(ops))
+		for _, op := range ops {
+			opIds = append(opIds, int32(op))
+		}
+		acl.Actions = append(acl.Actions, &request.ActionEntry{
+			Action:      request.Action(action),
+			Subject:     rule["subject"].(string),
+			SubjectType: request.SubjectType(subjectType),
+			OpIds:       opIds,
+		})
+	}
+	return acl, nil
+}
+
+func flattenACL(acl *request.ACL) []interface{} {
+	rules := make([]interface{}, 0, len(acl.Actions))
+	for _, entry := range acl.Actions {
+		ops := make([]request.Op, 0, len(entry.OpIds))
+		for _, id := range entry.OpIds {
+			ops = append(ops, request.Op(id))
+		}
+		rules = append(rules, map[string]interface{}{
+			"action":       strings.ToLower(entry.Action.String()),
+			"subject":      entry.Subject,
+			"subject_type": strings.ToLower(entry.SubjectType.String()),
+			"operations":   flattenPermissions(ops),
+		})
+	}
+	return rules
+}
@@ -0,0 +1,172 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccShareBasic(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	srv.AddUser(testAccMemberEmail)
+	config := func(rules string) string {
+		return providerConfig + `
+			resource "ysafe_access_policy" "docs" {
+				name = "docs"
+			}
+
+			resource "ysafe_role" "readers" {
+				name        = "readers"
+				permissions = ["GetSecret"]
+			}
+
+			resource "ysafe_team" "platform" {
+				name  = "platform"
+				roles = [ysafe_role.readers.id]
+			}
+
+			resource "ysafe_share" "test" {
+				object_path = "/${ysafe_access_policy.docs.id}"
+				` + rules + `
+			}
+		`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(fmt.Sprintf(`
+					rule {
+						subject    = %q
+						operations = ["GetSecret", "ListSecrets"]
+					}
+				`, testAccMemberEmail)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_share.test", "id", "/docs"),
+					resource.TestCheckResourceAttr("ysafe_share.test", "owner_email", testAccEmail),
+					resource.TestCheckResourceAttr("ysafe_share.test", "rule.#", "1"),
+					resource.TestCheckResourceAttr("ysafe_share.test", "rule.0.action", "allow"),
+					resource.TestCheckResourceAttr("ysafe_share.test", "rule.0.subject_type", "user"),
+					resource.TestCheckResourceAttr("ysafe_share.test", "rule.0.operations.#", "2"),
+					testAccCheckShare(srv, "/docs", "ALLOW USER "+testAccMemberEmail+" [OpGetSecret OpListSecrets]"),
+				),
+			},
+			{
+				// Rules are updated in place.
+				Config: config(fmt.Sprintf(`
+					rule {
+						subject      = ysafe_team.platform.id
+						subject_type = "team"
+						operations   = ["GetSecret"]
+					}
+
+					rule {
+						action     = "deny"
+						subject    = %q
+						operations = ["AddSecret"]
+					}
+				`, testAccMemberEmail)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_share.test", "rule.#", "2"),
+					resource.TestCheckResourceAttr("ysafe_share.test", "rule.1.action", "deny"),
+					testAccCheckShare(srv, "/docs",
+						"ALLOW TEAM platform [OpGetSecret]",
+						"DENY USER "+testAccMemberEmail+" [OpAddSecret]"),
+				),
+			},
+			{
+				ResourceName:      "ysafe_share.test",
+				ImportState:       true,
+				ImportStateId:     "/docs",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccShareDrift(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	srv.AddUser(testAccMemberEmail)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	if err := c.CreateFolder(ctx, "/", "docs", nil); err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	config := providerConfig + fmt.Sprintf(`
+		resource "ysafe_share" "test" {
+			object_path = "/docs"
+
+			rule {
+				subject    = %q
+				operations = ["GetSecret"]
+			}
+		}
+	`, testAccMemberEmail)
+	want := "ALLOW USER " + testAccMemberEmail + " [OpGetSecret]"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// Rules changed outside of Terraform are put back.
+				PreConfig: func() {
+					err := c.UpdateShare(ctx, &request.ACL{ObjectPath: "/docs", Actions: []*request.ActionEntry{{
+						Action:  request.Action_DENY,
+						Subject: testAccMemberEmail,
+						OpIds:   []int32{int32(request.Op_OpGetSecret)},
+					}}})
+					if err != nil {
+						t.Fatalf("UpdateShare: %v", err)
+					}
+				},
+				Config: config,
+				Check:  testAccCheckShare(srv, "/docs", want),
+			},
+			{
+				// So is a share removed outside of Terraform.
+				PreConfig: func() {
+					if err := c.Unshare(ctx, "/docs"); err != nil {
+						t.Fatalf("Unshare: %v", err)
+					}
+				},
+				Config: config,
+				Check:  testAccCheckShare(srv, "/docs", want),
+			},
+		},
+	})
+}
+
+// testAccCheckShare compares the rules of the ACL of p with want, each
+// written as "ACTION SUBJECT_TYPE subject [ops]".
+func testAccCheckShare(srv *ysafetest.Server, p string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		acl, ok := srv.Share(p)
+		if !ok {
+			return fmt.Errorf("%s is not shared", p)
+		}
+		var got []string
+		for _, entry := range acl.Actions {
+			ops := make([]request.Op, 0, len(entry.OpIds))
+			for _, id := range entry.OpIds {
+				ops = append(ops, request.Op(id))
+			}
+			got = append(got, fmt.Sprintf("%v %v %s %v", entry.Action, entry.SubjectType, entry.Subject, ops))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("%s has rules %q, want %q", p, got, want)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceTeam() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTeamCreate,
+		ReadContext:   resourceTeamRead,
+		UpdateContext: resourceTeamUpdate,
+		DeleteContext: resourceTeamDelete,
+		CustomizeDiff: resourceTeamCustomizeDiff,
+		Description:   "Manages a team: a named group of roles that can be given to users together.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Unique name of the team.",
+			},
+			"roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				Description: "Names of the roles of the team. Planning fails when one of them does not exist; " +
+					"reference roles created in the same configuration by id, such as `ysafe_role.example.id`, " +
+					"so that the check waits until they are created.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hex encoded uuid of the team.",
+			},
+		},
+	}
+}
+
+func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	name := d.Get("name").(string)
+	err := client.AddTeam(ctx, &request.AddTeam{
+		Name:  name,
+		Roles: expandStringSet(d.Get("roles").(*schema.Set)),
+	})
+	if err != nil {
+		return errorDiags("Add Team failed", err, cty.GetAttrPath("name"))
+	}
+	d.SetId(name)
+
+	return resourceTeamRead(ctx, d, m)
+}
+
+func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	team, err := client.GetTeam(ctx, d.Id())
+	if isNotFound(err) {
+		// Deleted outside of Terraform; plan to create it again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read Team failed", err, cty.GetAttrPath("name"))
+	}
+	roles := team.Roles
+	if len(team.RoleUuids) > 0 {
+		byUUID, err := roleNamesByUUID(ctx, client)
+		if err != nil {
+			return errorDiags("Read Team failed", err, cty.GetAttrPath("roles"))
+		}
+		roles = roleNames(byUUID, team.RoleUuids)
+	}
+	d.Set("name", team.Name)
+	d.Set("roles", roles)
+	d.Set("uuid", hex.EncodeToString(team.Uuid))
+	return nil
+}
+
+func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	err := client.UpdateTeam(ctx, &request.UpdateTeam{
+		Name:  d.Id(),
+		Roles: expandStringSet(d.Get("roles").(*schema.Set)),
+	})
+	if err != nil {
+		return errorDiags("Update Team failed", err, cty.GetAttrPath("roles"))
+	}
+	return resourceTeamRead(ctx, d, m)
+}
+
+func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	err := client.RemoveTeam(ctx, d.Id())
+	if err != nil && !isNotFound(err) {
+		return errorDiags("Delete Team failed", err, cty.GetAttrPath("name"))
+	}
+	return nil
+}
+
+// resourceTeamCustomizeDiff fails the plan when a role of the team does not
+// exist, rather than leaving it to AddTeam or UpdateTeam during apply.
+func resourceTeamCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("roles") || !d.NewValueKnown("roles") {
+		return nil
+	}
+	c, _ := m.(*client.Client)
+	if c == nil {
+		return nil
+	}
+	return checkRolesExist(ctx, c, expandStringSet(d.Get("roles").(*schema.Set)))
+}
@@ -0,0 +1,159 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTeamBasic(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	config := func(roles string) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_role" "readers" {
+				name        = "readers"
+				permissions = ["GetSecret"]
+			}
+
+			resource "ysafe_role" "writers" {
+				name        = "writers"
+				permissions = ["AddSecret", "UpdateSecret"]
+			}
+
+			resource "ysafe_team" "test" {
+				name  = "platform"
+				roles = %s
+			}
+		`, roles)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`[ysafe_role.readers.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_team.test", "id", "platform"),
+					resource.TestCheckResourceAttr("ysafe_team.test", "roles.#", "1"),
+					resource.TestCheckTypeSetElemAttr("ysafe_team.test", "roles.*", "readers"),
+					resource.TestCheckResourceAttrSet("ysafe_team.test", "uuid"),
+					testAccCheckTeamRoles(srv, "platform", "readers"),
+				),
+			},
+			{
+				// Roles are updated in place.
+				Config: config(`[ysafe_role.readers.id, ysafe_role.writers.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_team.test", "roles.#", "2"),
+					testAccCheckTeamRoles(srv, "platform", "readers", "writers"),
+				),
+			},
+			{
+				ResourceName:      "ysafe_team.test",
+				ImportState:       true,
+				ImportStateId:     "platform",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccTeamDrift(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	for _, name := range []string{"readers", "writers"} {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+	config := providerConfig + `
+		resource "ysafe_team" "test" {
+			name  = "platform"
+			roles = ["readers", "writers"]
+		}
+	`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					if err := c.UpdateTeam(ctx, &request.UpdateTeam{Name: "platform", Roles: []string{"readers"}}); err != nil {
+						t.Fatalf("UpdateTeam: %v", err)
+					}
+				},
+				Config: config,
+				Check:  testAccCheckTeamRoles(srv, "platform", "readers", "writers"),
+			},
+			{
+				PreConfig: func() {
+					if err := c.RemoveRole(ctx, "writers"); err != nil {
+						t.Fatalf("RemoveRole: %v", err)
+					}
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile(`role "writers" does not exist`),
+			},
+		},
+	})
+}
+
+func TestAccTeamMissingRole(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					resource "ysafe_team" "test" {
+						name  = "platform"
+						roles = ["nobody", "ghosts"]
+					}
+				`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`roles "ghosts", "nobody" do not exist`),
+			},
+		},
+	})
+	if _, ok := srv.Team("platform"); ok {
+		t.Error("team created despite the missing roles")
+	}
+}
+
+func testAccCheckTeamRoles(srv *ysafetest.Server, name string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		team, ok := srv.Team(name)
+		if !ok {
+			return fmt.Errorf("team %s not found", name)
+		}
+		var got []string
+		for _, uuid := range team.RoleUuids {
+			for _, role := range want {
+				if r, ok := srv.Role(role); ok && string(r.Uuid) == string(uuid) {
+					got = append(got, role)
+				}
+			}
+		}
+		sort.Strings(got)
+		if len(got) != len(team.RoleUuids) || strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Errorf("team %s has %d roles %v, want %v", name, len(team.RoleUuids), got, want)
+		}
+		return nil
+	}
+}
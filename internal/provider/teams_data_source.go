@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTeams() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceTeamsRead,
+		Description: "Lists the teams of the organization, optionally with their roles. Expanding the teams " +
+			"reads every team and, to name their roles, every role, one request each.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"expand": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read every team to fill in `teams`. Default false, which only lists the names.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the teams, sorted.",
+			},
+			"teams": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The teams, sorted by name. Only filled when `expand` is set.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the team.",
+						},
+						"roles": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Description: "Names of the roles of the team, sorted. A role that was removed is listed " +
+								"by its hex encoded uuid.",
+						},
+						"uuid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hex encoded uuid of the team.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTeamsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	list, err := client.ListTeams(ctx)
+	if err != nil {
+		return errorDiags("List Teams failed", err, nil)
+	}
+	sort.Strings(list)
+
+	names := []string{}
+	teams := []interface{}{}
+	expand := d.Get("expand").(bool)
+	// Looked up once the first team that names its roles by uuid is read.
+	var byUUID map[string]string
+	for _, name := range list {
+		if !expand {
+			names = append(names, name)
+			continue
+		}
+		team, err := client.GetTeam(ctx, name)
+		if isNotFound(err) {
+			// Removed since it was listed.
+			continue
+		}
+		if err != nil {
+			return errorDiags("Read Team failed", err, nil)
+		}
+		roles := append([]string{}, team.Roles...)
+		if len(team.RoleUuids) > 0 {
+			if byUUID == nil {
+				if byUUID, err = roleNamesByUUID(ctx, client); err != nil {
+					return errorDiags("Read Team failed", err, nil)
+				}
+			}
+			roles = roleNames(byUUID, team.RoleUuids)
+		}
+		sort.Strings(roles)
+		names = append(names, name)
+		teams = append(teams, map[string]interface{}{
+			"name":  name,
+			"roles": roles,
+			"uuid":  hex.EncodeToString(team.Uuid),
+		})
+	}
+
+	if expand {
+		d.SetId("teams expanded")
+	} else {
+		d.SetId("teams")
+	}
+	d.Set("names", names)
+	d.Set("teams", teams)
+	return nil
+}
@@ -0,0 +1,56 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccTeamsDataSource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	for _, name := range []string{"readers", "writers"} {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+	teams := map[string][]string{
+		"platform": {"writers", "readers"},
+		"audit":    {"readers"},
+	}
+	for name, roles := range teams {
+		if err := c.AddTeam(ctx, &request.AddTeam{Name: name, Roles: roles}); err != nil {
+			t.Fatalf("AddTeam: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					data "ysafe_teams" "names" {}
+
+					data "ysafe_teams" "expanded" {
+						expand = true
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ysafe_teams.names", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.names", "names.0", "audit"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.names", "teams.#", "0"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.expanded", "teams.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.expanded", "teams.1.name", "platform"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.expanded", "teams.1.roles.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.expanded", "teams.1.roles.0", "readers"),
+					resource.TestCheckResourceAttr("data.ysafe_teams.expanded", "teams.1.roles.1", "writers"),
+					resource.TestCheckResourceAttrSet("data.ysafe_teams.expanded", "teams.1.uuid"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceUserAssignment() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserAssignmentCreate,
+		ReadContext:   resourceUserAssignmentRead,
+		UpdateContext: resourceUserAssignmentUpdate,
+		DeleteContext: resourceUserAssignmentDelete,
+		CustomizeDiff: resourceUserAssignmentCustomizeDiff,
+		Description: "Manages the roles and teams of a member of the organization. By default the resource is " +
+			"authoritative: roles and teams given to the user outside of Terraform are removed. With `additive` " +
+			"it only adds its own roles and teams and leaves the others alone.",
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserAssignmentImportState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"email": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Email of the user.",
+			},
+			"roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				Description: "Names of the roles of the user. Planning fails when one of them does not exist.",
+			},
+			"teams": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				Description: "Names of the teams of the user. Planning fails when one of them does not exist.",
+			},
+			"additive": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Only add `roles` and `teams` to the user, keeping the ones given outside of Terraform, " +
+					"and only remove them again on destroy. Default false, which makes `roles` and `teams` the " +
+					"complete list.",
+			},
+		},
+	}
+}
+
+func resourceUserAssignmentImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("email", d.Id())
+	d.Set("additive", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceUserAssignmentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	email := d.Get("email").(string)
+	roles := stringSet(d.Get("roles"))
+	teams := stringSet(d.Get("teams"))
+	if d.Get("additive").(bool) {
+		current, err := currentRolesAndTeams(ctx, client, email)
+		if err != nil {
+			return errorDiags("Assign Roles and Teams failed", err, cty.GetAttrPath("email"))
+		}
+		roles = current.roles.Union(roles)
+		teams = current.teams.Union(teams)
+	}
+	if err := client.SetRolesTeamsToUser(ctx, email, expandStringSet(roles), expandStringSet(teams)); err != nil {
+		return errorDiags("Assign Roles and Teams failed", err, cty.GetAttrPath("email"))
+	}
+	d.SetId(email)
+
+	return resourceUserAssignmentRead(ctx, d, m)
+}
+
+func resourceUserAssignmentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	current, err := currentRolesAndTeams(ctx, client, d.Id())
+	if isNotFound(err) {
+		// The user left the organization; plan to assign again.
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return errorDiags("Read Roles and Teams failed", err, cty.GetAttrPath("email"))
+	}
+	roles, teams := current.roles, current.teams
+	if d.Get("additive").(bool) {
+		// Only what this resource added is tracked, so that a role it
+		// added and someone removed shows up as drift.
+		roles = roles.Intersection(stringSet(d.Get("roles")))
+		teams = teams.Intersection(stringSet(d.Get("teams")))
+	}
+	d.Set("email", d.Id())
+	d.Set("roles", roles)
+	d.Set("teams", teams)
+	return nil
+}
+
+func resourceUserAssignmentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	roles := stringSet(d.Get("roles"))
+	teams := stringSet(d.Get("teams"))
+	if d.Get("additive").(bool) {
+		current, err := currentRolesAndTeams(ctx, client, d.Id())
+		if err != nil {
+			return errorDiags("Assign Roles and Teams failed", err, cty.GetAttrPath("email"))
+		}
+		// Drop what this resource no longer adds. Right after switching
+		// from authoritative mode the old values are everything the user
+		// had, so nothing is dropped then.
+		if !d.HasChange("additive") {
+			oldRoles, _ := d.GetChange("roles")
+			oldTeams, _ := d.GetChange("teams")
+			current.roles = current.roles.Difference(stringSet(oldRoles))
+			current.teams = current.teams.Difference(stringSet(oldTeams))
+		}
+		roles = current.roles.Union(roles)
+		teams = current.teams.Union(teams)
+	}
+	if err := client.SetRolesTeamsToUser(ctx, d.Id(), expandStringSet(roles), expandStringSet(teams)); err != nil {
+		return errorDiags("Assign Roles and Teams failed", err, cty.GetAttrPath("email"))
+	}
+	return resourceUserAssignmentRead(ctx, d, m)
+}
+
+func resourceUserAssignmentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	var roles, teams []string
+	if d.Get("additive").(bool) {
+		current, err := currentRolesAndTeams(ctx, client, d.Id())
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return errorDiags("Remove Roles and Teams failed", err, cty.GetAttrPath("email"))
+		}
+		roles = expandStringSet(current.roles.Difference(stringSet(d.Get("roles"))))
+		teams = expandStringSet(current.teams.Difference(stringSet(d.Get("teams"))))
+	}
+	err := client.SetRolesTeamsToUser(ctx, d.Id(), roles, teams)
+	if err != nil && !isNotFound(err) {
+		return errorDiags("Remove Roles and Teams failed", err, cty.GetAttrPath("email"))
+	}
+	return nil
+}
+
+// resourceUserAssignmentCustomizeDiff fails the plan when a role or team
+// does not exist.
+func resourceUserAssignmentCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	c, _ := m.(*client.Client)
+	if c == nil {
+		return nil
+	}
+	if d.HasChange("roles") && d.NewValueKnown("roles") {
+		if err := checkRolesExist(ctx, c, expandStringSet(d.Get("roles").(*schema.Set))); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("teams") && d.NewValueKnown("teams") {
+		if err := checkTeamsExist(ctx, c, expandStringSet(d.Get("teams").(*schema.Set))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rolesAndTeams struct {
+	roles, teams *schema.Set
+}
+
+func currentRolesAndTeams(ctx context.Context, c *client.Client, email string) (*rolesAndTeams, error) {
+	roles, teams, err := c.GetRolesAndTeamsOfUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	r := &rolesAndTeams{
+		roles: schema.NewSet(schema.HashString, nil),
+		teams: schema.NewSet(schema.HashString, nil),
+	}
+	for _, role := range roles {
+		r.roles.Add(role)
+	}
+	for _, team := range teams {
+		r.teams.Add(team)
+	}
+	return r, nil
+}
+
+// stringSet copies a set of strings from the schema into a set hashed with
+// schema.HashString, like the sets of currentRolesAndTeams. Set operations
+// only match elements of sets that share a hash function.
+func stringSet(v interface{}) *schema.Set {
+	return schema.NewSet(schema.HashString, v.(*schema.Set).List())
+}
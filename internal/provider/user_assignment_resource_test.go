@@ -0,0 +1,185 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/ysafetest"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccMemberEmail = "member@example.com"
+
+func TestAccUserAssignmentBasic(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	srv.AddUser(testAccMemberEmail)
+	config := func(roles, teams string) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_role" "readers" {
+				name        = "readers"
+				permissions = ["GetSecret"]
+			}
+
+			resource "ysafe_role" "writers" {
+				name        = "writers"
+				permissions = ["AddSecret"]
+			}
+
+			resource "ysafe_team" "platform" {
+				name  = "platform"
+				roles = [ysafe_role.readers.id]
+			}
+
+			resource "ysafe_user_assignment" "test" {
+				email = %q
+				roles = %s
+				teams = %s
+			}
+		`, testAccMemberEmail, roles, teams)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`[ysafe_role.readers.id]`, `[ysafe_team.platform.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_user_assignment.test", "id", testAccMemberEmail),
+					resource.TestCheckResourceAttr("ysafe_user_assignment.test", "additive", "false"),
+					resource.TestCheckTypeSetElemAttr("ysafe_user_assignment.test", "roles.*", "readers"),
+					resource.TestCheckTypeSetElemAttr("ysafe_user_assignment.test", "teams.*", "platform"),
+					testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "readers", "platform"),
+				),
+			},
+			{
+				Config: config(`[ysafe_role.readers.id, ysafe_role.writers.id]`, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_user_assignment.test", "roles.#", "2"),
+					resource.TestCheckResourceAttr("ysafe_user_assignment.test", "teams.#", "0"),
+					testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "readers,writers", ""),
+				),
+			},
+			{
+				ResourceName:      "ysafe_user_assignment.test",
+				ImportState:       true,
+				ImportStateId:     testAccMemberEmail,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccUserAssignmentDrift(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	srv.AddUser(testAccMemberEmail)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	for _, name := range []string{"readers", "writers"} {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+	config := providerConfig + fmt.Sprintf(`
+		resource "ysafe_user_assignment" "test" {
+			email = %q
+			roles = ["readers"]
+		}
+	`, testAccMemberEmail)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckProjectDestroy(srv),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// A role given outside of Terraform is taken away again.
+				PreConfig: func() {
+					if err := c.SetRolesTeamsToUser(ctx, testAccMemberEmail, []string{"readers", "writers"}, nil); err != nil {
+						t.Fatalf("SetRolesTeamsToUser: %v", err)
+					}
+				},
+				Config: config,
+				Check:  testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "readers", ""),
+			},
+		},
+	})
+}
+
+func TestAccUserAssignmentAdditive(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	srv.AddUser(testAccMemberEmail)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	for _, name := range []string{"readers", "writers", "auditors"} {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+	if err := c.SetRolesTeamsToUser(ctx, testAccMemberEmail, []string{"auditors"}, nil); err != nil {
+		t.Fatalf("SetRolesTeamsToUser: %v", err)
+	}
+	config := func(roles string) string {
+		return providerConfig + fmt.Sprintf(`
+			resource "ysafe_user_assignment" "test" {
+				email    = %q
+				roles    = %s
+				additive = true
+			}
+		`, testAccMemberEmail, roles)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckProjectDestroy(srv),
+			// The role given outside of Terraform outlives the resource.
+			testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "auditors", ""),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["readers"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ysafe_user_assignment.test", "roles.#", "1"),
+					testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "auditors,readers", ""),
+				),
+			},
+			{
+				Config: config(`["writers"]`),
+				Check:  testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "auditors,writers", ""),
+			},
+			{
+				// A role this resource added and someone took away is added again.
+				PreConfig: func() {
+					if err := c.SetRolesTeamsToUser(ctx, testAccMemberEmail, []string{"auditors"}, nil); err != nil {
+						t.Fatalf("SetRolesTeamsToUser: %v", err)
+					}
+				},
+				Config: config(`["writers"]`),
+				Check:  testAccCheckUserRolesAndTeams(srv, testAccMemberEmail, "auditors,writers", ""),
+			},
+		},
+	})
+}
+
+// testAccCheckUserRolesAndTeams compares the roles and teams of a member with
+// comma separated, sorted lists of names.
+func testAccCheckUserRolesAndTeams(srv *ysafetest.Server, email, roles, teams string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		gotRoles, gotTeams, ok := srv.UserRolesAndTeams(email)
+		if !ok {
+			return fmt.Errorf("user %s not found", email)
+		}
+		if strings.Join(gotRoles, ",") != roles || strings.Join(gotTeams, ",") != teams {
+			return fmt.Errorf("user %s has roles %v and teams %v, want %q and %q", email, gotRoles, gotTeams, roles, teams)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"terraform-provider-izysafe/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceUsers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUsersRead,
+		Description: "Lists the members of the organization with their roles and teams. The provider pages " +
+			"through every user; the filters are applied by the provider.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"team": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only list members of this team.",
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only list users given this role directly, rather than through one of their teams.",
+			},
+			"emails": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Emails of the listed users, sorted.",
+			},
+			"users": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The listed users, sorted by email.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"email": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Email of the user.",
+						},
+						"teams": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names of the teams of the user, sorted.",
+						},
+						"roles": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Names of the roles given to the user directly, sorted.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*client.Client)
+	if client == nil {
+		return diag.Errorf("Client is nil, please check the token and pin. Contact support if the issue persists.")
+	}
+	team := d.Get("team").(string)
+	role := d.Get("role").(string)
+
+	list, err := client.ListUsers(ctx, 0)
+	if err != nil {
+		return errorDiags("List Users failed", err, nil)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Email < list[j].Email })
+
+	emails := []string{}
+	users := []interface{}{}
+	for _, u := range list {
+		if team != "" && !slices.Contains(u.TeamsOfUser, team) {
+			continue
+		}
+		if role != "" && !slices.Contains(u.RolesOfUser, role) {
+			continue
+		}
+		teams := append([]string(nil), u.TeamsOfUser...)
+		roles := append([]string(nil), u.RolesOfUser...)
+		sort.Strings(teams)
+		sort.Strings(roles)
+		emails = append(emails, u.Email)
+		users = append(users, map[string]interface{}{
+			"email": u.Email,
+			"teams": teams,
+			"roles": roles,
+		})
+	}
+
+	id := "users"
+	if team != "" {
+		id += " team=" + team
+	}
+	if role != "" {
+		id += " role=" + role
+	}
+	d.SetId(id)
+	d.Set("emails", emails)
+	d.Set("users", users)
+	return nil
+}
@@ -0,0 +1,77 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"terraform-provider-izysafe/internal/proto/request"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUsersDataSource(t *testing.T) {
+	srv, providerConfig := testAccServer(t)
+	c := testAccClient(t, srv)
+	ctx := context.Background()
+	for _, name := range []string{"readers", "writers"} {
+		if err := c.AddRole(ctx, &request.AddRole{Name: name, Permissions: []request.Op{request.Op_OpGetSecret}}); err != nil {
+			t.Fatalf("AddRole: %v", err)
+		}
+	}
+	if err := c.AddTeam(ctx, &request.AddTeam{Name: "platform", Roles: []string{"readers"}}); err != nil {
+		t.Fatalf("AddTeam: %v", err)
+	}
+	// More users than fit on a page.
+	for i := 0; i < 120; i++ {
+		srv.AddUser(fmt.Sprintf("user%03d@example.com", i))
+	}
+	assign := map[string][2][]string{
+		"user007@example.com": {{"readers", "writers"}, {"platform"}},
+		"user110@example.com": {{"writers"}, nil},
+		"user042@example.com": {nil, {"platform"}},
+	}
+	for email, a := range assign {
+		if err := c.SetRolesTeamsToUser(ctx, email, a[0], a[1]); err != nil {
+			t.Fatalf("SetRolesTeamsToUser: %v", err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					data "ysafe_users" "all" {}
+
+					data "ysafe_users" "platform" {
+						team = "platform"
+					}
+
+					data "ysafe_users" "writers" {
+						role = "writers"
+					}
+
+					data "ysafe_users" "platform_writers" {
+						team = "platform"
+						role = "writers"
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					// The owner signed in with the provider is a member as well.
+					resource.TestCheckResourceAttr("data.ysafe_users.all", "emails.#", "121"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform", "emails.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform", "users.0.email", "user007@example.com"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform", "users.0.roles.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform", "users.0.roles.1", "writers"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform", "users.1.email", "user042@example.com"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform", "users.1.roles.#", "0"),
+					resource.TestCheckResourceAttr("data.ysafe_users.writers", "emails.#", "2"),
+					resource.TestCheckResourceAttr("data.ysafe_users.writers", "emails.1", "user110@example.com"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform_writers", "emails.#", "1"),
+					resource.TestCheckResourceAttr("data.ysafe_users.platform_writers", "emails.0", "user007@example.com"),
+				),
+			},
+		},
+	})
+}
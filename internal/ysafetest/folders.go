@@ -0,0 +1,82 @@
+package ysafetest
+
+import (
+	"path"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type folder struct {
+	meta    *response.Folder
+	trashed bool
+}
+
+// Folder returns the metadata of the folder at p, unless it does not exist
+// or was moved to the trash.
+func (s *Server) Folder(p string) (*response.Folder, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.folders[p]
+	if !ok || f.trashed {
+		return nil, false
+	}
+	return f.meta, true
+}
+
+func (s *Server) getMetaFromPath(req *request.GetMetaFromPath) *response.Response {
+	result := &response.GetMetaFromPath{Status: response.Status_OBJECT_NOT_FOUND}
+	if f, ok := s.folders[req.Path]; ok && f.trashed == req.Trashed {
+		result.Status = response.Status_SUCCESS
+		result.Meta = &response.MetaObj{Meta: &response.MetaObj_FolderMeta{FolderMeta: f.meta}}
+	}
+	return &response.Response{Operation: &response.Response_GetMetaFromPath{GetMetaFromPath: result}}
+}
+
+func (s *Server) createFolder(email string, req *request.CreateFolder) *response.Response {
+	result := &response.CreateFolder{}
+	p := path.Join(req.ParentPath, req.Name)
+	switch {
+	case req.Name == "" || !path.IsAbs(req.ParentPath):
+		result.Status = response.Status_INVALID_REQUEST
+	case req.ParentPath != "/" && s.folders[req.ParentPath] == nil:
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	case s.folders[p] != nil && !s.folders[p].trashed:
+		result.Status = response.Status_OBJECT_EXISTS
+	default:
+		meta := &response.Folder{
+			Name:             req.Name,
+			ParentFolder:     req.ParentPath,
+			Uuid:             newUUID(),
+			Owner:            email,
+			CreationDate:     now(),
+			LastModifiedDate: now(),
+		}
+		if req.Policy != nil {
+			policy, err := proto.Marshal(req.Policy)
+			if err != nil {
+				result.Status = response.Status_INVALID_REQUEST
+				break
+			}
+			meta.Policy = policy
+		}
+		s.folders[p] = &folder{meta: meta}
+	}
+	return &response.Response{Operation: &response.Response_CreateFolder{CreateFolder: result}}
+}
+
+func (s *Server) removeFolder(req *request.RemoveFolder) *response.Response {
+	result := &response.RemoveFolder{}
+	f, ok := s.folders[req.FolderFullPath]
+	switch {
+	case !ok || f.trashed:
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	case req.IsPerm:
+		delete(s.folders, req.FolderFullPath)
+	default:
+		f.trashed = true
+	}
+	return &response.Response{Operation: &response.Response_RemoveFolder{RemoveFolder: result}}
+}
@@ -0,0 +1,118 @@
+package ysafetest
+
+import (
+	"bytes"
+	"sort"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+)
+
+type pin struct {
+	name       string
+	email      string
+	pin        string
+	ttl        uint64
+	created    uint64
+	data       []byte
+	idToClient []byte
+	ops        []request.AllowedPinOp
+}
+
+// HasPin reports whether email has a pin called name.
+func (s *Server) HasPin(email, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findPin(email, name) != nil
+}
+
+func (s *Server) findPin(email, name string) *pin {
+	for _, p := range s.pins {
+		if p.email == email && p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// addPin creates the pin and an account for its token, so that the token
+// can be used to sign in like one issued by the real service.
+func (s *Server) addPin(email string, req *request.AddPin) *response.Response {
+	result := &response.AddPin{Email: email}
+	name := req.GetName()
+	switch {
+	case req.Email != email:
+		result.Status = response.Status_ACCESS_DENIED
+	case len(req.Pin) != 6:
+		result.Status = response.Status_INVALID_REQUEST
+		result.Message = message("pin must have six digits")
+	case s.findPin(email, name) != nil:
+		result.Status = response.Status_OBJECT_EXISTS
+		result.Message = message("a pin called %q exists", name)
+	default:
+		p := &pin{
+			name:       name,
+			email:      email,
+			pin:        req.Pin,
+			ttl:        req.Ttl,
+			created:    now(),
+			data:       newUUID(),
+			idToClient: newUUID(),
+			ops:        req.AllowedOps,
+		}
+		s.pins[string(p.idToClient)] = p
+		s.accounts[string(p.data)] = account{email: email, pin: p.pin}
+		result.Data = p.data
+		result.IdToClient = p.idToClient
+	}
+	return &response.Response{Operation: &response.Response_AddPin{AddPin: result}}
+}
+
+func (s *Server) updatePinOps(email string, req *request.UpdatePinOps) *response.Response {
+	result := &response.UpdatePinOps{}
+	p := s.findPin(email, req.PinName)
+	if p == nil || !bytes.Equal(p.data, req.Data) {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		p.ops = req.AllowedOps
+	}
+	return &response.Response{Operation: &response.Response_UpdatePinOps{UpdatePinOps: result}}
+}
+
+func (s *Server) deletePin(email string, req *request.DeletePin) *response.Response {
+	result := &response.DeletePin{}
+	p, ok := s.pins[string(req.IdSentToClient)]
+	if !ok || p.email != email || !bytes.Equal(p.data, req.Data) {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		delete(s.pins, string(req.IdSentToClient))
+		delete(s.accounts, string(p.data))
+	}
+	return &response.Response{Operation: &response.Response_DeletePin{DeletePin: result}}
+}
+
+func (s *Server) listPins(email string) *response.Response {
+	result := &response.ListPins{IsLast: true}
+	for _, p := range s.pins {
+		if p.email != email {
+			continue
+		}
+		var ops []int32
+		for _, op := range p.ops {
+			ops = append(ops, int32(op))
+		}
+		result.PinObjects = append(result.PinObjects, &response.PinInList{
+			Name:          p.name,
+			CreationTime:  p.created,
+			Ttl:           p.ttl,
+			AllowedPinOps: ops,
+			IdToClient:    p.idToClient,
+		})
+	}
+	sort.Slice(result.PinObjects, func(i, j int) bool {
+		return result.PinObjects[i].Name < result.PinObjects[j].Name
+	})
+	total := uint64(len(result.PinObjects))
+	result.TotalCount = &total
+	return &response.Response{Operation: &response.Response_ListPins{ListPins: result}}
+}
@@ -0,0 +1,171 @@
+package ysafetest
+
+import (
+	"sort"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Role returns the role called name.
+func (s *Server) Role(name string) (*request.Role, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	role, ok := s.roles[name]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(role).(*request.Role), true
+}
+
+// Team returns the team called name.
+func (s *Server) Team(name string) (*request.Team, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	team, ok := s.teams[name]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(team).(*request.Team), true
+}
+
+func (s *Server) addRole(req *request.AddRole) *response.Response {
+	result := &response.AddRole{}
+	if req.Name == "" {
+		result.Status = response.Status_INVALID_REQUEST
+	} else if _, ok := s.roles[req.Name]; ok {
+		result.Status = response.Status_OBJECT_EXISTS
+		result.Message = message("role %s exists", req.Name)
+	} else {
+		s.roles[req.Name] = &request.Role{
+			Name:        req.Name,
+			Permissions: req.Permissions,
+			Uuid:        newUUID(),
+		}
+	}
+	return &response.Response{Operation: &response.Response_AddRole{AddRole: result}}
+}
+
+func (s *Server) updateRole(req *request.UpdateRole) *response.Response {
+	result := &response.UpdateRole{}
+	if role, ok := s.roles[req.Name]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		role.Permissions = req.Permissions
+	}
+	return &response.Response{Operation: &response.Response_UpdateRole{UpdateRole: result}}
+}
+
+func (s *Server) removeRole(req *request.RemoveRole) *response.Response {
+	result := &response.RemoveRole{}
+	if _, ok := s.roles[req.Name]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		delete(s.roles, req.Name)
+	}
+	return &response.Response{Operation: &response.Response_RemoveRole{RemoveRole: result}}
+}
+
+func (s *Server) getRole(req *request.GetRole) *response.Response {
+	result := &response.GetRole{}
+	if role, ok := s.roles[req.Name]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		result.Role, _ = proto.Marshal(role)
+	}
+	return &response.Response{Operation: &response.Response_GetRole{GetRole: result}}
+}
+
+func (s *Server) listRoles() *response.Response {
+	result := &response.ListRoles{}
+	for name := range s.roles {
+		result.Roles = append(result.Roles, name)
+	}
+	sort.Strings(result.Roles)
+	return &response.Response{Operation: &response.Response_ListRoles{ListRoles: result}}
+}
+
+// roleUuids resolves role names, failing on the first one that does not
+// exist.
+func (s *Server) roleUuids(names []string) ([][]byte, *string) {
+	var uuids [][]byte
+	for _, name := range names {
+		role, ok := s.roles[name]
+		if !ok {
+			return nil, message("role %s not found", name)
+		}
+		uuids = append(uuids, role.Uuid)
+	}
+	return uuids, nil
+}
+
+// addTeam stores the team with the uuids of its roles only, as the service
+// does.
+func (s *Server) addTeam(req *request.AddTeam) *response.Response {
+	result := &response.AddTeam{}
+	uuids, missing := s.roleUuids(req.Roles)
+	switch {
+	case req.Name == "":
+		result.Status = response.Status_INVALID_REQUEST
+	case s.teams[req.Name] != nil:
+		result.Status = response.Status_OBJECT_EXISTS
+		result.Message = message("team %s exists", req.Name)
+	case missing != nil:
+		result.Status = response.Status_OBJECT_NOT_FOUND
+		result.Message = missing
+	default:
+		s.teams[req.Name] = &request.Team{
+			Name:      req.Name,
+			RoleUuids: uuids,
+			Uuid:      newUUID(),
+		}
+	}
+	return &response.Response{Operation: &response.Response_AddTeam{AddTeam: result}}
+}
+
+func (s *Server) updateTeam(req *request.UpdateTeam) *response.Response {
+	result := &response.UpdateTeam{}
+	team, ok := s.teams[req.Name]
+	uuids, missing := s.roleUuids(req.Roles)
+	switch {
+	case !ok:
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	case missing != nil:
+		result.Status = response.Status_OBJECT_NOT_FOUND
+		result.Message = missing
+	default:
+		team.RoleUuids = uuids
+	}
+	return &response.Response{Operation: &response.Response_UpdateTeam{UpdateTeam: result}}
+}
+
+func (s *Server) removeTeam(req *request.RemoveTeam) *response.Response {
+	result := &response.RemoveTeam{}
+	if _, ok := s.teams[req.Name]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		delete(s.teams, req.Name)
+	}
+	return &response.Response{Operation: &response.Response_RemoveTeam{RemoveTeam: result}}
+}
+
+func (s *Server) getTeam(req *request.GetTeam) *response.Response {
+	result := &response.GetTeam{}
+	if team, ok := s.teams[req.Name]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		result.Team, _ = proto.Marshal(team)
+	}
+	return &response.Response{Operation: &response.Response_GetTeam{GetTeam: result}}
+}
+
+func (s *Server) listTeams() *response.Response {
+	result := &response.ListTeams{}
+	for name := range s.teams {
+		result.Teams = append(result.Teams, name)
+	}
+	sort.Strings(result.Teams)
+	return &response.Response{Operation: &response.Response_ListTeams{ListTeams: result}}
+}
@@ -0,0 +1,248 @@
+package ysafetest
+
+import (
+	"crypto/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type secret struct {
+	meta    *response.Secret
+	deleted bool
+}
+
+// Secret returns the stored secret at path, including its versions, unless
+// it does not exist or was deleted.
+func (s *Server) Secret(path string) (*response.Secret, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, ok := s.secrets[path]
+	if !ok || sec.deleted {
+		return nil, false
+	}
+	return proto.Clone(sec.meta).(*response.Secret), true
+}
+
+// SecretDeleted reports whether the secret at path is soft deleted, as
+// opposed to live or destroyed.
+func (s *Server) SecretDeleted(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, ok := s.secrets[path]
+	return ok && sec.deleted
+}
+
+// AgeSecret moves the last rotation of the secret at path back by d, as if
+// that much time had passed since.
+func (s *Server) AgeSecret(path string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sec, ok := s.secrets[path]; ok {
+		sec.meta.LastRotatedTime -= uint64(d / time.Second)
+	}
+}
+
+// secretData converts between the request and response flavour of
+// SecretData, which share their wire format.
+func secretData(data *request.SecretData) (*response.SecretData, error) {
+	b, err := proto.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var out response.SecretData
+	if err := proto.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *Server) liveSecret(path string) *secret {
+	sec, ok := s.secrets[path]
+	if !ok || sec.deleted {
+		return nil
+	}
+	return sec
+}
+
+func (s *Server) addSecret(req *request.AddSecret) *response.Response {
+	result := &response.AddSecret{}
+	data, err := secretData(req.SecretData)
+	switch {
+	case err != nil || req.SecretData == nil || !strings.HasPrefix(req.Path, "/"):
+		result.Status = response.Status_INVALID_REQUEST
+	case s.secrets[req.Path] != nil:
+		result.Status = response.Status_OBJECT_EXISTS
+		result.Message = message("secret %s exists", req.Path)
+	default:
+		created := now()
+		s.secrets[req.Path] = &secret{meta: &response.Secret{
+			Path:            req.Path,
+			Uuid:            newUUID(),
+			Expiry:          req.GetExpiry(),
+			RotateInterval:  req.GetRotateInterval(),
+			LastRotatedTime: created,
+			Versions: []*response.SecretVersion{{
+				Id:           1,
+				CreationTime: created,
+				SecretData:   data,
+			}},
+		}}
+	}
+	return &response.Response{Operation: &response.Response_AddSecret{AddSecret: result}}
+}
+
+// updateSecret stores the new data as the next version of the secret, which
+// counts as a rotation.
+func (s *Server) updateSecret(req *request.UpdateSecret) *response.Response {
+	result := &response.UpdateSecret{}
+	sec := s.liveSecret(req.Path)
+	if sec == nil {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+		return &response.Response{Operation: &response.Response_UpdateSecret{UpdateSecret: result}}
+	}
+	if req.Expiry != nil {
+		sec.meta.Expiry = *req.Expiry
+	}
+	if req.SecretData != nil {
+		data, err := secretData(req.SecretData)
+		if err != nil {
+			result.Status = response.Status_INVALID_REQUEST
+			return &response.Response{Operation: &response.Response_UpdateSecret{UpdateSecret: result}}
+		}
+		versions := sec.meta.Versions
+		updated := now()
+		sec.meta.Versions = append(versions, &response.SecretVersion{
+			Id:           versions[len(versions)-1].Id + 1,
+			CreationTime: updated,
+			SecretData:   data,
+		})
+		sec.meta.LastRotatedTime = updated
+	}
+	return &response.Response{Operation: &response.Response_UpdateSecret{UpdateSecret: result}}
+}
+
+func (s *Server) getSecret(req *request.GetSecret) *response.Response {
+	result := &response.GetSecret{}
+	if sec := s.liveSecret(req.Path); sec == nil {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		sec.meta.IsExpired = sec.meta.Expiry != 0 && sec.meta.Expiry < now()
+		data, err := proto.Marshal(sec.meta)
+		if err != nil {
+			result.Status = response.Status_BACKEND_ERROR
+		}
+		result.Data = data
+	}
+	return &response.Response{Operation: &response.Response_GetSecret{GetSecret: result}}
+}
+
+func (s *Server) deleteSecret(req *request.DeleteSecret) *response.Response {
+	result := &response.DeleteSecret{}
+	if sec := s.liveSecret(req.Path); sec == nil {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		sec.deleted = true
+	}
+	return &response.Response{Operation: &response.Response_DeleteSecret{DeleteSecret: result}}
+}
+
+func (s *Server) undeleteSecret(req *request.UndeleteSecret) *response.Response {
+	result := &response.UndeleteSecret{}
+	if sec, ok := s.secrets[req.Path]; !ok || !sec.deleted {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		sec.deleted = false
+	}
+	return &response.Response{Operation: &response.Response_UndeleteSecret{UndeleteSecret: result}}
+}
+
+func (s *Server) destroySecret(req *request.DestroySecret) *response.Response {
+	result := &response.DestroySecret{}
+	if _, ok := s.secrets[req.Path]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		delete(s.secrets, req.Path)
+	}
+	return &response.Response{Operation: &response.Response_DestroySecret{DestroySecret: result}}
+}
+
+// sortedSecrets returns the live secrets ordered by path.
+func (s *Server) sortedSecrets() []*response.Secret {
+	var secrets []*response.Secret
+	for _, sec := range s.secrets {
+		if !sec.deleted {
+			secrets = append(secrets, sec.meta)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Path < secrets[j].Path })
+	return secrets
+}
+
+func (s *Server) listSecrets() *response.Response {
+	result := &response.ListSecrets{}
+	for _, sec := range s.sortedSecrets() {
+		result.Secrets = append(result.Secrets, proto.Clone(sec).(*response.Secret))
+	}
+	return &response.Response{Operation: &response.Response_ListSecrets{ListSecrets: result}}
+}
+
+func (s *Server) quickListSecrets() *response.Response {
+	result := &response.QuickListSecrets{}
+	for _, sec := range s.sortedSecrets() {
+		result.QuickSecrets = append(result.QuickSecrets, &response.QuickSecret{
+			Path:   sec.Path,
+			Expiry: sec.Expiry,
+		})
+	}
+	return &response.Response{Operation: &response.Response_QuickListSecrets{QuickListSecrets: result}}
+}
+
+// listSecretSubkeys returns the names directly below path, the way a
+// directory listing would.
+func (s *Server) listSecretSubkeys(req *request.ListSecretSubkeys) *response.Response {
+	result := &response.ListSecretSubkeys{}
+	prefix := strings.TrimSuffix(req.Path, "/") + "/"
+	seen := map[string]bool{}
+	for _, sec := range s.sortedSecrets() {
+		rest, ok := strings.CutPrefix(sec.Path, prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.Index(rest, "/"); i >= 0 {
+			rest = rest[:i+1]
+		}
+		if !seen[rest] {
+			seen[rest] = true
+			result.Subkeys = append(result.Subkeys, rest)
+		}
+	}
+	return &response.Response{Operation: &response.Response_ListSecretSubkeys{ListSecretSubkeys: result}}
+}
+
+const alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+func getRandomBytes(req *request.GetRandomBytes) *response.Response {
+	result := &response.GetRandomBytes{}
+	if req.Size == 0 || req.Size > 1<<16 {
+		result.Status = response.Status_INVALID_REQUEST
+		result.Message = message("size %d is out of range", req.Size)
+		return &response.Response{Operation: &response.Response_GetRandomBytes{GetRandomBytes: result}}
+	}
+	b := make([]byte, req.Size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	if req.IsAlphanumeric {
+		for i := range b {
+			b[i] = alphanumeric[int(b[i])%len(alphanumeric)]
+		}
+	}
+	result.RandomBytes = b
+	return &response.Response{Operation: &response.Response_GetRandomBytes{GetRandomBytes: result}}
+}
@@ -0,0 +1,370 @@
+// Package ysafetest runs an in-process ysafe server so that the client and
+// the provider can be tested without network access or real credentials.
+package ysafetest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+// Organization is the name of the organization every account of the server
+// is a member of.
+const Organization = "example"
+
+// Server is a TLS WebSocket server speaking the ysafe protobuf protocol.
+type Server struct {
+	// URL is the wss:// endpoint to configure the client with.
+	URL string
+
+	srv      *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu       sync.Mutex
+	accounts map[string]account
+	requests []Request
+	conns    map[*websocket.Conn]bool
+	signIns  int
+
+	latency     time.Duration
+	inFlight    int
+	maxInFlight int
+	handler     HandlerFunc
+
+	folders map[string]*folder
+	pins    map[string]*pin
+	secrets map[string]*secret
+	roles   map[string]*request.Role
+	teams   map[string]*request.Team
+	users   map[string]*user
+	shares  map[string]*request.ACL
+	owner   string
+}
+
+// HandlerFunc answers req on behalf of the account email. Returning nil
+// falls back to the built-in behaviour of the server.
+type HandlerFunc func(email string, req *request.Request) *response.Response
+
+type account struct {
+	email string
+	pin   string
+}
+
+// Request is a request received on an authenticated session.
+type Request struct {
+	// Email is the account the session that sent the request signed in as.
+	Email   string
+	Request *request.Request
+}
+
+// NewServer starts a server that is closed when the test finishes.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		accounts: map[string]account{},
+		conns:    map[*websocket.Conn]bool{},
+		folders:  map[string]*folder{},
+		pins:     map[string]*pin{},
+		secrets:  map[string]*secret{},
+		roles:    map[string]*request.Role{},
+		teams:    map[string]*request.Team{},
+		users:    map[string]*user{},
+		shares:   map[string]*request.ACL{},
+	}
+	s.srv = httptest.NewTLSServer(http.HandlerFunc(s.serveWS))
+	s.URL = "wss" + strings.TrimPrefix(s.srv.URL, "https")
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down and closes all open sessions.
+func (s *Server) Close() {
+	s.srv.CloseClientConnections()
+	s.srv.Close()
+}
+
+// CACertPEM returns the PEM encoded certificate the server is using.
+func (s *Server) CACertPEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.srv.Certificate().Raw,
+	}))
+}
+
+// AddAccount registers a member of the organization that can sign in with
+// the returned token and pin, and returns the token in the base64 form the
+// provider expects.
+func (s *Server) AddAccount(email, pin string) string {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[string(data)] = account{email: email, pin: pin}
+	if s.users[email] == nil {
+		s.users[email] = &user{userID: newUUID()}
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// DropConnections closes every open session from the server side, the way
+// an idle timeout on a load balancer would.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// SignIns returns the number of successful sign ins so far.
+func (s *Server) SignIns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.signIns
+}
+
+// Handle installs h in front of the built-in behaviour, which lets tests
+// script responses such as failures.
+func (s *Server) Handle(h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = h
+}
+
+// SetLatency delays every response after sign in by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// MaxInFlight returns the highest number of requests that were being served
+// at the same time.
+func (s *Server) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight
+}
+
+// Requests returns every request received after sign in, in arrival order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	var email string
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req request.Request
+		if err := proto.Unmarshal(msg, &req); err != nil {
+			return
+		}
+		var resp *response.Response
+		if signIn := req.GetSignIn(); signIn != nil {
+			email, resp = s.signIn(signIn)
+		} else if email == "" {
+			// Nothing but sign in is answered before authentication.
+			resp = &response.Response{}
+		} else {
+			resp = s.serve(email, &req)
+		}
+		resp.Id = req.Id
+		out, err := proto.Marshal(resp)
+		if err != nil {
+			return
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, out); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) serve(email string, req *request.Request) *response.Response {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	latency := s.latency
+	s.mu.Unlock()
+
+	time.Sleep(latency)
+
+	s.mu.Lock()
+	s.inFlight--
+	s.requests = append(s.requests, Request{Email: email, Request: req})
+	h := s.handler
+	s.mu.Unlock()
+
+	if h != nil {
+		if resp := h(email, req); resp != nil {
+			return resp
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handle(email, req)
+}
+
+func (s *Server) signIn(req *request.SignIn) (string, *response.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.accounts[string(req.Data)]
+	if !ok || acc.pin != req.GetPin() {
+		return "", &response.Response{Operation: &response.Response_SignIn{SignIn: &response.SignIn{
+			Status: response.Status_AUTHENTICATION_FAILURE,
+		}}}
+	}
+	s.signIns++
+	organization := Organization
+	return acc.email, &response.Response{Operation: &response.Response_SignIn{SignIn: &response.SignIn{
+		Email:       acc.email,
+		Orgnization: &organization,
+		Status:      response.Status_SUCCESS,
+	}}}
+}
+
+// handle answers req on behalf of the signed in user. It is called with s.mu
+// held.
+func (s *Server) handle(email string, req *request.Request) *response.Response {
+	switch op := req.Operation.(type) {
+	case *request.Request_Echo:
+		return &response.Response{Operation: &response.Response_Echo{Echo: &response.Echo{
+			Flags: op.Echo.Flags,
+			Data:  op.Echo.Data,
+		}}}
+
+	case *request.Request_GetMetaFromPath:
+		return s.getMetaFromPath(op.GetMetaFromPath)
+	case *request.Request_CreateFolder:
+		return s.createFolder(email, op.CreateFolder)
+	case *request.Request_RemoveFolder:
+		return s.removeFolder(op.RemoveFolder)
+
+	case *request.Request_AddPin:
+		return s.addPin(email, op.AddPin)
+	case *request.Request_UpdatePinOps:
+		return s.updatePinOps(email, op.UpdatePinOps)
+	case *request.Request_DeletePin:
+		return s.deletePin(email, op.DeletePin)
+	case *request.Request_ListPins:
+		return s.listPins(email)
+
+	case *request.Request_AddSecret:
+		return s.addSecret(op.AddSecret)
+	case *request.Request_UpdateSecret:
+		return s.updateSecret(op.UpdateSecret)
+	case *request.Request_GetSecret:
+		return s.getSecret(op.GetSecret)
+	case *request.Request_DeleteSecret:
+		return s.deleteSecret(op.DeleteSecret)
+	case *request.Request_UndeleteSecret:
+		return s.undeleteSecret(op.UndeleteSecret)
+	case *request.Request_DestroySecret:
+		return s.destroySecret(op.DestroySecret)
+	case *request.Request_ListSecrets:
+		return s.listSecrets()
+	case *request.Request_QuickListSecrets:
+		return s.quickListSecrets()
+	case *request.Request_ListSecretSubkeys:
+		return s.listSecretSubkeys(op.ListSecretSubkeys)
+	case *request.Request_GetRandomBytes:
+		return getRandomBytes(op.GetRandomBytes)
+
+	case *request.Request_AddRole:
+		return s.addRole(op.AddRole)
+	case *request.Request_UpdateRole:
+		return s.updateRole(op.UpdateRole)
+	case *request.Request_RemoveRole:
+		return s.removeRole(op.RemoveRole)
+	case *request.Request_GetRole:
+		return s.getRole(op.GetRole)
+	case *request.Request_ListRoles:
+		return s.listRoles()
+
+	case *request.Request_AddTeam:
+		return s.addTeam(op.AddTeam)
+	case *request.Request_UpdateTeam:
+		return s.updateTeam(op.UpdateTeam)
+	case *request.Request_RemoveTeam:
+		return s.removeTeam(op.RemoveTeam)
+	case *request.Request_GetTeam:
+		return s.getTeam(op.GetTeam)
+	case *request.Request_ListTeams:
+		return s.listTeams()
+
+	case *request.Request_SetRolesTeamsToUser:
+		return s.setRolesTeamsToUser(op.SetRolesTeamsToUser)
+	case *request.Request_GetRolesAndTeamsOfUser:
+		return s.getRolesAndTeamsOfUser(op.GetRolesAndTeamsOfUser)
+	case *request.Request_InviteUser:
+		return s.inviteUser(op.InviteUser)
+	case *request.Request_RemoveUser:
+		return s.removeUser(op.RemoveUser)
+	case *request.Request_GetUser:
+		return s.getUser(op.GetUser)
+	case *request.Request_ListUsers:
+		return s.listUsers(op.ListUsers)
+
+	case *request.Request_Share:
+		return s.share(email, op.Share)
+	case *request.Request_UpdateShare:
+		return s.updateShare(op.UpdateShare)
+	case *request.Request_Unshare:
+		return s.unshare(op.Unshare)
+	case *request.Request_ListShares:
+		return s.listShares(op.ListShares)
+	}
+	// Unknown operations get an empty response, which the client rejects.
+	return &response.Response{}
+}
+
+func newUUID() []byte {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		panic(err)
+	}
+	return uuid
+}
+
+func now() uint64 {
+	return uint64(time.Now().Unix())
+}
+
+func message(format string, args ...any) *string {
+	m := fmt.Sprintf(format, args...)
+	return &m
+}
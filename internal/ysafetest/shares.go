@@ -0,0 +1,99 @@
+package ysafetest
+
+import (
+	"fmt"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Share returns the ACL of the shared object at p.
+func (s *Server) Share(p string) (*request.ACL, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acl, ok := s.shares[p]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(acl).(*request.ACL), true
+}
+
+// checkACL returns the status of a request to share according to acl: the
+// object must be a folder and every subject must exist. Unlike most replies,
+// those to share requests carry a plain message.
+func (s *Server) checkACL(acl *request.ACL) (response.Status, string) {
+	if acl == nil || acl.ObjectPath == "" {
+		return response.Status_INVALID_REQUEST, ""
+	}
+	if f, ok := s.folders[acl.ObjectPath]; !ok || f.trashed {
+		return response.Status_OBJECT_NOT_FOUND, fmt.Sprintf("folder %s not found", acl.ObjectPath)
+	}
+	for _, entry := range acl.Actions {
+		switch entry.SubjectType {
+		case request.SubjectType_USER:
+			if s.users[entry.Subject] == nil {
+				return response.Status_OBJECT_NOT_FOUND, fmt.Sprintf("user %s not found", entry.Subject)
+			}
+		case request.SubjectType_TEAM:
+			if s.teams[entry.Subject] == nil {
+				return response.Status_OBJECT_NOT_FOUND, fmt.Sprintf("team %s not found", entry.Subject)
+			}
+		default:
+			return response.Status_INVALID_REQUEST, ""
+		}
+	}
+	return response.Status_SUCCESS, ""
+}
+
+func (s *Server) share(email string, req *request.Share) *response.Response {
+	result := &response.Share{}
+	result.Status, result.Message = s.checkACL(req.Acl)
+	if result.Status == response.Status_SUCCESS {
+		if _, ok := s.shares[req.Acl.ObjectPath]; ok {
+			result.Status = response.Status_OBJECT_EXISTS
+			result.Message = fmt.Sprintf("%s is already shared", req.Acl.ObjectPath)
+		} else {
+			acl := proto.Clone(req.Acl).(*request.ACL)
+			acl.OwnerEmail = email
+			s.shares[acl.ObjectPath] = acl
+		}
+	}
+	return &response.Response{Operation: &response.Response_Share{Share: result}}
+}
+
+func (s *Server) updateShare(req *request.UpdateShare) *response.Response {
+	result := &response.UpdateShare{}
+	result.Status, result.Message = s.checkACL(req.Acl)
+	if result.Status == response.Status_SUCCESS {
+		if old, ok := s.shares[req.Acl.ObjectPath]; !ok {
+			result.Status = response.Status_OBJECT_NOT_FOUND
+		} else {
+			acl := proto.Clone(req.Acl).(*request.ACL)
+			acl.OwnerEmail = old.OwnerEmail
+			s.shares[acl.ObjectPath] = acl
+		}
+	}
+	return &response.Response{Operation: &response.Response_UpdateShare{UpdateShare: result}}
+}
+
+func (s *Server) unshare(req *request.Unshare) *response.Response {
+	result := &response.Unshare{}
+	if _, ok := s.shares[req.ObjectPath]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		delete(s.shares, req.ObjectPath)
+	}
+	return &response.Response{Operation: &response.Response_Unshare{Unshare: result}}
+}
+
+func (s *Server) listShares(req *request.ListShares) *response.Response {
+	result := &response.ListShares{}
+	if acl, ok := s.shares[req.ObjectPath]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	} else {
+		result.Acl, _ = proto.Marshal(acl)
+	}
+	return &response.Response{Operation: &response.Response_ListShares{ListShares: result}}
+}
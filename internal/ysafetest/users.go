@@ -0,0 +1,211 @@
+package ysafetest
+
+import (
+	"sort"
+
+	"terraform-provider-izysafe/internal/proto/request"
+	"terraform-provider-izysafe/internal/proto/response"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type user struct {
+	// userID is set once the user has an account; invited users that have
+	// not signed up yet have none.
+	userID []byte
+	roles  []string
+	teams  []string
+}
+
+// AddUser adds a member to the organization without a way to sign in.
+func (s *Server) AddUser(email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.users[email] == nil {
+		s.users[email] = &user{userID: newUUID()}
+	}
+}
+
+// User reports whether email is a member of the organization, and whether
+// they are still only invited.
+func (s *Server) User(email string) (exists, invited bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[email]
+	return ok, ok && u.userID == nil
+}
+
+// AcceptInvitation turns the invited user email into a member, the way
+// signing up through the invitation would.
+func (s *Server) AcceptInvitation(email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.users[email]; ok && u.userID == nil {
+		u.userID = newUUID()
+	}
+}
+
+// SetOwner makes email the account of the organization itself, which
+// GetUser describes as an Organization rather than a User and RemoveUser
+// refuses to remove.
+func (s *Server) SetOwner(email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owner = email
+}
+
+func (s *Server) inviteUser(req *request.InviteUser) *response.Response {
+	result := &response.InviteUser{Organization: req.Organization, Email: req.Email}
+	switch {
+	case req.Email == "":
+		result.Status = response.Status_INVALID_REQUEST
+	case req.Organization != Organization:
+		result.Status = response.Status_ACCESS_DENIED
+		result.Message = message("not a member of organization %s", req.Organization)
+	case s.users[req.Email] != nil || req.Email == s.owner:
+		result.Status = response.Status_OBJECT_EXISTS
+		result.Message = message("user %s is already a member", req.Email)
+	default:
+		s.users[req.Email] = &user{}
+	}
+	return &response.Response{Operation: &response.Response_InviteUser{InviteUser: result}}
+}
+
+func (s *Server) removeUser(req *request.RemoveUser) *response.Response {
+	result := &response.RemoveUser{Email: req.Email}
+	switch {
+	case req.GetOrganization() != Organization:
+		result.Status = response.Status_ACCESS_DENIED
+		result.Message = message("not a member of organization %s", req.GetOrganization())
+	case req.Email == s.owner:
+		result.Status = response.Status_ACCESS_DENIED
+		result.Message = message("%s owns the organization", req.Email)
+	case s.users[req.Email] == nil:
+		result.Status = response.Status_OBJECT_NOT_FOUND
+	default:
+		delete(s.users, req.Email)
+	}
+	return &response.Response{Operation: &response.Response_RemoveUser{RemoveUser: result}}
+}
+
+func (s *Server) getUser(req *request.GetUser) *response.Response {
+	result := &response.GetUser{}
+	if req.Email == s.owner && s.owner != "" {
+		result.User = &response.UserMeta{User: &response.UserMeta_OrganizationObj{OrganizationObj: &response.Organization{
+			Name:  Organization,
+			Email: req.Email,
+		}}}
+	} else if u, ok := s.users[req.Email]; ok {
+		result.User = &response.UserMeta{User: &response.UserMeta_UserObj{UserObj: &response.User{
+			Organization: Organization,
+			Email:        req.Email,
+			UserId:       u.userID,
+		}}}
+	} else {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+		result.Message = message("user %s not found", req.Email)
+	}
+	return &response.Response{Operation: &response.Response_GetUser{GetUser: result}}
+}
+
+// UserRolesAndTeams returns the names of the roles and teams given to the
+// member email.
+func (s *Server) UserRolesAndTeams(email string) (roles, teams []string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[email]
+	if !ok {
+		return nil, nil, false
+	}
+	return append([]string(nil), u.roles...), append([]string(nil), u.teams...), true
+}
+
+// setRolesTeamsToUser replaces the roles and teams of a member.
+func (s *Server) setRolesTeamsToUser(req *request.SetRolesTeamsToUser) *response.Response {
+	result := &response.SetRolesTeamsToUser{}
+	u, ok := s.users[req.EmailOfUser]
+	if !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+		result.Message = message("user %s not found", req.EmailOfUser)
+		return &response.Response{Operation: &response.Response_SetRolesTeamsToUser{SetRolesTeamsToUser: result}}
+	}
+	for _, name := range req.NameOfRoles {
+		if s.roles[name] == nil {
+			result.Status = response.Status_OBJECT_NOT_FOUND
+			result.Message = message("role %s not found", name)
+		}
+	}
+	for _, name := range req.NameOfTeams {
+		if s.teams[name] == nil {
+			result.Status = response.Status_OBJECT_NOT_FOUND
+			result.Message = message("team %s not found", name)
+		}
+	}
+	if result.Status == response.Status_SUCCESS {
+		u.roles = sortedCopy(req.NameOfRoles)
+		u.teams = sortedCopy(req.NameOfTeams)
+	}
+	return &response.Response{Operation: &response.Response_SetRolesTeamsToUser{SetRolesTeamsToUser: result}}
+}
+
+func (s *Server) getRolesAndTeamsOfUser(req *request.GetRolesAndTeamsOfUser) *response.Response {
+	result := &response.GetRolesAndTeamsOfUser{}
+	if u, ok := s.users[req.EmailOfUser]; !ok {
+		result.Status = response.Status_OBJECT_NOT_FOUND
+		result.Message = message("user %s not found", req.EmailOfUser)
+	} else {
+		result.NameOfRoles = u.roles
+		result.NameOfTeams = u.teams
+	}
+	return &response.Response{Operation: &response.Response_GetRolesAndTeamsOfUser{GetRolesAndTeamsOfUser: result}}
+}
+
+func sortedCopy(names []string) []string {
+	out := append([]string(nil), names...)
+	sort.Strings(out)
+	return out
+}
+
+// listUsers pages through the users sorted by email. The serial number in
+// the page token is the number of users already returned.
+func (s *Server) listUsers(req *request.ListUsers) *response.Response {
+	result := &response.ListUsers{}
+	emails := make([]string, 0, len(s.users))
+	for email := range s.users {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+
+	var start uint64
+	if len(req.PageTokenListUser) > 0 {
+		var token response.PageTokenListUser
+		if err := proto.Unmarshal(req.PageTokenListUser, &token); err != nil {
+			result.Status = response.Status_INVALID_REQUEST
+			return &response.Response{Operation: &response.Response_ListUsers{ListUsers: result}}
+		}
+		start = token.LastSerialNumber
+	}
+	size := uint64(req.GetPageSize())
+	if size == 0 {
+		size = 10
+	}
+	end := start + size
+	if end > uint64(len(emails)) {
+		end = uint64(len(emails))
+	}
+	for i := start; i < end; i++ {
+		u := s.users[emails[i]]
+		result.Users = append(result.Users, &response.UserMetaForList{
+			Email:       emails[i],
+			RolesOfUser: u.roles,
+			TeamsOfUser: u.teams,
+		})
+	}
+	if end < uint64(len(emails)) {
+		result.PageTokenListUser, _ = proto.Marshal(&response.PageTokenListUser{
+			LastSerialNumber: end,
+			PageSize:         uint32(size),
+		})
+	}
+	return &response.Response{Operation: &response.Response_ListUsers{ListUsers: result}}
+}